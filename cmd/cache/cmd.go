@@ -0,0 +1,77 @@
+//Package cache manage the cache sub-commands of the CLI.
+package cache
+
+import (
+	"fmt"
+
+	"github.com/marema31/kamino/cmd/common"
+	"github.com/marema31/kamino/recipe"
+	"github.com/marema31/kamino/step"
+	"github.com/spf13/cobra"
+)
+
+//AddCommands adds all subcommands to RootCmd.
+func AddCommands(cmd *cobra.Command) {
+	cmd.AddCommand(
+		NewCacheCommand(),
+	)
+}
+
+//NewCacheCommand declare the cache sub commands.
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the caches of the synchronization steps",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newCacheActionCommand("list", "Show the cache files with their age, TTL, row count and source"),
+		newCacheActionCommand("refresh", "Recreate the cache files from their source"),
+		newCacheActionCommand("clear", "Remove the cache files"),
+	)
+
+	return cmd
+}
+
+func newCacheActionCommand(action string, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:                   action + " <recipe> ... <recipe>",
+		Short:                 short,
+		Long:                  ``,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cookbook := recipe.New(&step.Factory{}, common.Timeout, common.Retry, true, true, false, common.DryRun)
+			return Action(action, cookbook, args)
+		},
+	}
+}
+
+//Action run the cache action on all the sync steps of the recipes.
+func Action(action string, cookbook recipe.Cooker, args []string) error {
+	log := common.Logger.WithField("action", "cache-"+action)
+
+	recipes, err := common.FindRecipes(log, args)
+	if err != nil {
+		return err
+	}
+
+	err = cookbook.Load(common.Ctx, log, common.CfgFolder, recipes, common.Tags, nil, []string{"sync"})
+	if err != nil {
+		return fmt.Errorf("error while loading the recipes: %w", err)
+	}
+
+	superseed := common.CreateSuperseed()
+	superseed["sync.cacheAction"] = action
+
+	err = cookbook.PostLoad(log, superseed)
+	if err != nil {
+		return fmt.Errorf("error while postloading the recipes: %w", err)
+	}
+
+	if cookbook.Do(common.Ctx, log) {
+		return fmt.Errorf("a step had an error: %w", common.ErrStep)
+	}
+
+	return nil
+}
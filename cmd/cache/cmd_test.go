@@ -0,0 +1,77 @@
+package cache_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/marema31/kamino/cmd/cache"
+	"github.com/marema31/kamino/cmd/common"
+)
+
+func TestActionOk(t *testing.T) {
+	common.CfgFolder = "testdata/good"
+	ck := &mockedCookbook{}
+	err := cache.Action("list", ck, []string{"recipe1ok", "recipe2ok"})
+	if err != nil {
+		t.Errorf("Action should not returns an error, returned: %v", err)
+	}
+
+	if !ck.called {
+		t.Errorf("Do should be called")
+	}
+}
+
+func TestActionFindRecipesError(t *testing.T) {
+	common.CfgFolder = "testdata"
+	ck := &mockedCookbook{}
+	err := cache.Action("list", ck, []string{})
+	if err == nil {
+		t.Errorf("Action should returns an error")
+	}
+
+	if ck.called {
+		t.Errorf("Do should not be called")
+	}
+}
+
+func TestActionLoadError(t *testing.T) {
+	common.CfgFolder = "testdata/good"
+	ck := &mockedCookbook{}
+	ck.errorLoad = fmt.Errorf("fake error")
+	err := cache.Action("clear", ck, []string{"recipe1ok", "recipe2ok"})
+	if err == nil {
+		t.Errorf("Action should returns an error")
+	}
+
+	if ck.called {
+		t.Errorf("Do should not be called")
+	}
+}
+
+func TestActionPostLoadError(t *testing.T) {
+	common.CfgFolder = "testdata/good"
+	ck := &mockedCookbook{}
+	ck.errorPostLoad = fmt.Errorf("fake error")
+	err := cache.Action("refresh", ck, []string{"recipe1ok", "recipe2ok"})
+	if err == nil {
+		t.Errorf("Action should returns an error")
+	}
+
+	if ck.called {
+		t.Errorf("Do should not be called")
+	}
+}
+
+func TestActionDoError(t *testing.T) {
+	common.CfgFolder = "testdata/good"
+	ck := &mockedCookbook{}
+	ck.doReturnValue = true
+	err := cache.Action("refresh", ck, []string{"recipe1ok", "recipe2ok"})
+	if err == nil {
+		t.Errorf("Action should returns an error")
+	}
+
+	if !ck.called {
+		t.Errorf("Do should be called")
+	}
+}
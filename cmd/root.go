@@ -3,13 +3,16 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/marema31/kamino/cmd/cache"
 	"github.com/marema31/kamino/cmd/common"
 	"github.com/marema31/kamino/cmd/migrate"
+	"github.com/marema31/kamino/progress"
 )
 
 // RootCmd represents the base command when called without any subcommands.
@@ -52,6 +55,7 @@ func init() {
 	rootCmd.PersistentFlags().DurationVar(&common.Timeout, "connection-timeout", time.Millisecond*2, "timeout of each database connection retry") //nolint:gomnd //Default value
 	rootCmd.PersistentFlags().BoolVarP(&common.Verbose, "verbose", "v", false, "logs more verbose")
 	migrate.AddCommands(rootCmd)
+	cache.AddCommands(rootCmd)
 }
 
 // GetLogger returns the logger instancied at initialization phase.
@@ -98,5 +102,10 @@ func InitConfig() {
 
 	if common.Quiet {
 		common.Logger.SetLevel(logrus.PanicLevel)
+	} else if progress.IsTerminal(os.Stderr) {
+		// The logs are written through the display to stay above the progression lines
+		display := progress.NewDisplay(os.Stderr)
+		common.Logger.SetOutput(display)
+		progress.SetDisplay(display)
 	}
 }
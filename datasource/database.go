@@ -16,6 +16,8 @@ import (
 	_ "github.com/go-sql-driver/mysql" // Mysql library dynamically called by database/sql
 	_ "github.com/lib/pq"              //Postgres library dynamically called by database/sql
 	"github.com/spf13/viper"
+
+	"github.com/marema31/kamino/throttle"
 )
 
 type dbInfo struct {
@@ -175,6 +177,9 @@ func loadDatabaseDatasource(log *logrus.Entry, filename string, v *viper.Viper,
 		ds.urlNoDb = fmt.Sprintf("host=%s port=%s user=%s password=%s %s", ds.host, ds.port, ds.admin, ds.adminPw, urlOptions)
 	}
 
+	ds.limiter = throttle.New(v.GetInt("throttle.rows"), v.GetInt("throttle.bytes"))
+	ds.hostLimiter = throttle.ForHost(fmt.Sprintf("%s:%s", ds.host, ds.port), v.GetInt("hostThrottle.rows"), v.GetInt("hostThrottle.bytes"))
+
 	return ds, nil
 }
 
@@ -268,6 +273,13 @@ func (ds *Datasource) OpenDatabase(log *logrus.Entry, admin bool, nodb bool) (*s
 
 	if db != nil {
 		//		logDb.Debug("The database is already opened, returning the current handler")
+		// Each OpenDatabase is balanced by a CloseDatabase, the handler must be counted even if it is reused
+		openedReadMutex.Lock()
+		if opened, ok := openedDatabase[URL]; ok {
+			opened.count++
+		}
+		openedReadMutex.Unlock()
+
 		return db, nil
 	}
 
@@ -56,6 +56,10 @@ func TestLoadMysqlCompleteEngine(t *testing.T) {
 	if len(ds.tags) != 0 && ds.tags[0] != "tagmc" {
 		t.Errorf("The tag should be found")
 	}
+
+	if len(ds.GetLimiters()) != 2 {
+		t.Errorf("The datasource and host limiters should be found")
+	}
 }
 
 func TestLoadMysqlMinimalEngine(t *testing.T) {
@@ -269,6 +273,43 @@ func TestDatabaseReOpen(t *testing.T) {
 		t.Errorf("Reopenning database should return the same object")
 	}
 }
+func TestDatabaseReOpenCounted(t *testing.T) {
+	mockingSQL = true
+	url := "bob:123soleil@tcp(localhost:1234)/dbcounted"
+	ds := Datasource{engine: Mysql, dstype: Database, url: url}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	db, err := ds.OpenDatabase(log, false, false)
+	if err != nil {
+		t.Fatalf("OpenDatabase should not returns an error, was: %v", err)
+	}
+
+	// The mocked sqlOpen does not register the handler
+	openedReadMutex.Lock()
+	openedDatabase[url] = &dbInfo{db: db, count: 1}
+	openedReadMutex.Unlock()
+
+	if _, err = ds.OpenDatabase(log, false, false); err != nil {
+		t.Fatalf("OpenDatabase should not returns an error, was: %v", err)
+	}
+
+	if err = ds.CloseDatabase(log, false, false); err != nil {
+		t.Errorf("CloseDatabase should not returns an error, was: %v", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		t.Errorf("The database should still be opened for the first user, ping returned: %v", err)
+	}
+
+	// The mocked database returns an error on its real close since it was not expected
+	ds.CloseDatabase(log, false, false) //nolint: errcheck
+
+	if err = db.Ping(); err == nil {
+		t.Errorf("The database should be closed when no more used")
+	}
+}
+
 func TestLoadNoTags(t *testing.T) {
 	dss, log := setupDatabaseTest()
 	_, err := dss.load(log, "testdata/good", "datasources", "mysqlnotag")
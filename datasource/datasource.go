@@ -15,6 +15,7 @@ import (
 	"github.com/Sirupsen/logrus"
 
 	"github.com/marema31/kamino/file"
+	"github.com/marema31/kamino/throttle"
 )
 
 //Datasourcer interface for allowing mocking of Datasource object.
@@ -27,10 +28,12 @@ type Datasourcer interface {
 	CloseFile(*logrus.Entry) error
 	CloseDatabase(*logrus.Entry, bool, bool) error
 	GetName() string
+	GetNamedTag(string) string
 	GetHash(*logrus.Entry, bool, bool) string
 	GetEngine() Engine
 	GetType() Type
 	IsTransaction() bool
+	GetLimiters() []*throttle.Limiter
 	IsTableEmpty(context.Context, *logrus.Entry, string) (bool, error)
 	IsTableExists(context.Context, *logrus.Entry, string) (bool, error)
 	Stat() (os.FileInfo, error)
@@ -50,6 +53,8 @@ const (
 	YAML Engine = iota
 	// CSV file engine
 	CSV Engine = iota
+	// Generator of synthetic records
+	Generator Engine = iota
 )
 
 //Type discriminate the type of datasource.
@@ -60,6 +65,8 @@ const (
 	Database Type = iota
 	// File (JSON,YAML,CSV, ...)
 	File Type = iota
+	// Synthetic (generator)
+	Synthetic Type = iota
 )
 
 // Datasource is handle to the corresponding datasource (either file/database).
@@ -86,6 +93,9 @@ type Datasource struct {
 	schema      string
 	file        file.File
 	tags        []string
+	limiter     *throttle.Limiter
+	hostLimiter *throttle.Limiter
+	generator   GeneratorConfig
 }
 
 //GetHash returns uniq hash for the datasource final destination (more than one datasource could have the same hash by example same database engine).
@@ -94,6 +104,8 @@ func (ds *Datasource) GetHash(log *logrus.Entry, admin bool, nodb bool) string {
 
 	if ds.dstype == File {
 		toHash = ds.file.FilePath
+	} else if ds.dstype == Synthetic {
+		toHash = "generator:" + ds.name
 	} else {
 		switch {
 		case nodb:
@@ -119,6 +131,8 @@ func StringToType(dsType string) (Type, error) {
 		return Database, nil
 	case "file", "files":
 		return File, nil
+	case "synthetic", "generator", "generators":
+		return Synthetic, nil
 	}
 
 	return File, fmt.Errorf("does not how to manage %s datasource type: %w", dsType, errWrongParameterValue)
@@ -153,6 +167,8 @@ func StringToEngine(engine string) (Engine, error) {
 		return YAML, nil
 	case "csv":
 		return CSV, nil
+	case "generator":
+		return Generator, nil
 	}
 
 	return CSV, fmt.Errorf("does not how to manage %s datasource engine: %w", engine, errWrongParameterValue)
@@ -187,6 +203,8 @@ func EngineToString(engine Engine) string {
 		return "yaml"
 	case CSV:
 		return "csv"
+	case Generator:
+		return "generator"
 	}
 
 	return "Unknown" // We will never arrive here
@@ -199,6 +217,8 @@ func TypeToString(dsType Type) string {
 		return "database"
 	case File:
 		return "file"
+	case Synthetic:
+		return "synthetic"
 	}
 
 	return "Unknown" // We will never arrive here
@@ -219,6 +239,19 @@ func (ds *Datasource) IsTransaction() bool {
 	return ds.transaction
 }
 
+//GetLimiters return the throughput limiters of the datasource and of its host.
+func (ds *Datasource) GetLimiters() []*throttle.Limiter {
+	limiters := make([]*throttle.Limiter, 0, 2)
+
+	for _, l := range []*throttle.Limiter{ds.limiter, ds.hostLimiter} {
+		if l != nil {
+			limiters = append(limiters, l)
+		}
+	}
+
+	return limiters
+}
+
 //GetNamedTag return the value of the tag with the provided name or "" if not exists.
 func (ds *Datasource) GetNamedTag(name string) string {
 	for _, tag := range ds.tags {
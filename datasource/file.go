@@ -11,6 +11,8 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"github.com/Sirupsen/logrus"
 	"github.com/spf13/viper"
+
+	"github.com/marema31/kamino/throttle"
 )
 
 // load a dile type datasource from the viper configuration.
@@ -68,6 +70,7 @@ func loadFileDatasource(log *logrus.Entry, recipePath string, filename string, v
 
 	ds.file.Zip = v.GetBool("zip")
 	ds.file.Gzip = v.GetBool("gzip")
+	ds.limiter = throttle.New(v.GetInt("throttle.rows"), v.GetInt("throttle.bytes"))
 	ds.file.ZippedExt = EngineToString(engine)
 
 	return ds, nil
@@ -0,0 +1,83 @@
+package datasource
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/marema31/kamino/throttle"
+)
+
+//GeneratorTable describes the records generated for a table of a generator datasource.
+type GeneratorTable struct {
+	Count   int               // Number of records generated
+	Columns map[string]string // Template generating the value of each column
+}
+
+//GeneratorDatasourcer is implemented by the generator datasources.
+type GeneratorDatasourcer interface {
+	GetGenerator() GeneratorConfig
+}
+
+//GeneratorConfig describes the tables of a generator datasource.
+type GeneratorConfig struct {
+	Seed   int64 // Seed of the random generators, the same seed generates the same records
+	Tables map[string]GeneratorTable
+}
+
+// load a generator type datasource from the viper configuration.
+func loadGeneratorDatasource(log *logrus.Entry, filename string, v *viper.Viper) (Datasource, error) {
+	log.Debugf("Loading %s generator datasource", filename)
+
+	var ds Datasource
+	ds.dstype = Synthetic
+	ds.engine = Generator
+	ds.name = filename
+
+	// Without seed the records are different on each run, the seed used is logged by the generation to be able to generate them again
+	ds.generator.Seed = time.Now().UnixNano()
+	if v.IsSet("seed") {
+		ds.generator.Seed = v.GetInt64("seed")
+	}
+
+	ds.generator.Tables = make(map[string]GeneratorTable)
+
+	for table := range v.GetStringMap("tables") {
+		sub := v.Sub("tables." + table)
+		if sub == nil {
+			return Datasource{}, fmt.Errorf("the table %s of the generator datasource %s is not a dictionary: %w", table, filename, errWrongParameterValue)
+		}
+
+		t := GeneratorTable{Count: sub.GetInt("count"), Columns: sub.GetStringMapString("columns")}
+
+		if t.Count < 0 {
+			return Datasource{}, fmt.Errorf("the number of records of the table %s must be positive: %w", table, errWrongParameterValue)
+		}
+
+		if len(t.Columns) == 0 {
+			return Datasource{}, fmt.Errorf("no column provided for the table %s: %w", table, errMissingParameter)
+		}
+
+		ds.generator.Tables[table] = t
+	}
+
+	if len(ds.generator.Tables) == 0 {
+		return Datasource{}, fmt.Errorf("no table provided for the generator datasource %s: %w", filename, errMissingParameter)
+	}
+
+	ds.tags = v.GetStringSlice("tags")
+	if len(ds.tags) == 0 {
+		ds.tags = []string{""}
+	}
+
+	ds.limiter = throttle.New(v.GetInt("throttle.rows"), v.GetInt("throttle.bytes"))
+
+	return ds, nil
+}
+
+//GetGenerator return the description of the tables of a generator datasource.
+func (ds *Datasource) GetGenerator() GeneratorConfig {
+	return ds.generator
+}
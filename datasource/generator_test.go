@@ -0,0 +1,38 @@
+package datasource
+
+import (
+	"testing"
+)
+
+func TestLoadGeneratorEngine(t *testing.T) {
+	dss, log := setupFileTest()
+	ds, err := dss.load(log, "testdata/good", "datasources", "generator")
+	if err != nil {
+		t.Fatalf("Load returns an error %v", err)
+	}
+
+	if ds.dstype != Synthetic {
+		t.Errorf("Should be recognized as synthetic datasource")
+	}
+
+	if ds.engine != Generator {
+		t.Errorf("Should be recognized as generator datasource but was recognized as '%s'", EngineToString(ds.GetEngine()))
+	}
+
+	config := ds.GetGenerator()
+	if config.Seed != 42 {
+		t.Errorf("The seed is %d", config.Seed)
+	}
+
+	if len(config.Tables) != 2 || config.Tables["pokemon"].Count != 1000 || config.Tables["types"].Columns["id"] != "{{ seq }}" {
+		t.Errorf("The tables are not correctly loaded: %v", config.Tables)
+	}
+}
+
+func TestLoadGeneratorNoTable(t *testing.T) {
+	dss, log := setupFileTest()
+	_, err := dss.load(log, "testdata/fail", "datasources", "nogeneratortable")
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+}
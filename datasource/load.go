@@ -101,6 +101,8 @@ func (dss *Datasources) load(log *logrus.Entry, recipePath string, subpath strin
 		return loadDatabaseDatasource(log, filename, v, e, dss.envVar, dss.conTimeout, dss.conRetry)
 	case JSON, YAML, CSV:
 		return loadFileDatasource(log, recipePath, filename, v, e, dss.envVar)
+	case Generator:
+		return loadGeneratorDatasource(log, filename, v)
 	}
 	//Should never come here, error will be raised by StringToEngine
 	return Datasource{}, fmt.Errorf("does not how to manage %s datasource engine: %w", engine, errWrongParameterValue)
@@ -0,0 +1,332 @@
+package filter
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Values recognized as booleans by the bool conversion.
+var (
+	castTrueValues  = map[string]bool{"1": true, "t": true, "true": true, "y": true, "yes": true, "on": true, "oui": true}
+	castFalseValues = map[string]bool{"0": true, "f": true, "false": true, "n": true, "no": true, "off": true, "non": true}
+)
+
+// Format of a number once the spaces and the thousands separators are removed.
+var castNumberRe = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+//castOperation is a conversion step of a column.
+type castOperation struct {
+	call
+	// Prepared values of the arguments
+	precision int
+	from      *time.Location
+	to        *time.Location
+	decoder   *encoding.Decoder
+	encoder   *encoding.Encoder
+}
+
+// CastFilter specific type for cast filter operation.
+type CastFilter struct {
+	columns map[string][]castOperation
+}
+
+//getEncoding return the encoding of a charset name, nil for UTF-8.
+func getEncoding(name string) (encoding.Encoding, error) {
+	e, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown charset %s: %w", name, errWrongParameterValue)
+	}
+
+	if n, _ := htmlindex.Name(e); n == "utf-8" {
+		return nil, nil
+	}
+
+	return e, nil
+}
+
+//prepare verify the number of arguments of the operation and compute the values needed to apply it.
+func (op *castOperation) prepare() error {
+	nbArgs := map[string][2]int{
+		"trim":        {0, 0},
+		"lower":       {0, 0},
+		"upper":       {0, 0},
+		"nullifempty": {0, 0},
+		"bool":        {0, 2},
+		"number":      {0, 2},
+		"date":        {2, 4},
+		"charset":     {1, 2},
+	}
+
+	n, ok := nbArgs[op.name]
+	if !ok {
+		return fmt.Errorf("unknown cast operation %s: %w", op.name, errWrongParameterValue)
+	}
+
+	if len(op.args) < n[0] || len(op.args) > n[1] {
+		return fmt.Errorf("cast operation %s needs between %d and %d arguments: %w", op.name, n[0], n[1], errWrongParameterValue)
+	}
+
+	var err error
+
+	switch op.name {
+	case "bool":
+		if len(op.args) == 1 {
+			return fmt.Errorf("cast operation bool needs the true and false values: %w", errWrongParameterValue)
+		}
+
+		if len(op.args) == 0 {
+			op.args = []string{"true", "false"}
+		}
+	case "number":
+		if len(op.args) == 0 {
+			op.args = []string{"."}
+		}
+
+		if op.args[0] != "." && op.args[0] != "," {
+			return fmt.Errorf("the decimal separator must be . or , not %s: %w", op.args[0], errWrongParameterValue)
+		}
+
+		op.precision = -1
+
+		if len(op.args) == 2 {
+			if op.precision, err = strconv.Atoi(op.args[1]); err != nil || op.precision < 0 {
+				return fmt.Errorf("the precision must be a positive number, not %s: %w", op.args[1], errWrongParameterValue)
+			}
+		}
+	case "date":
+		op.from = time.UTC
+
+		if len(op.args) > 2 {
+			if op.from, err = time.LoadLocation(op.args[2]); err != nil {
+				return fmt.Errorf("time zone %s: %w", op.args[2], err)
+			}
+		}
+
+		op.to = op.from
+
+		if len(op.args) > 3 {
+			if op.to, err = time.LoadLocation(op.args[3]); err != nil {
+				return fmt.Errorf("time zone %s: %w", op.args[3], err)
+			}
+		}
+	case "charset":
+		from, err := getEncoding(op.args[0])
+		if err != nil {
+			return err
+		}
+
+		if from != nil {
+			op.decoder = from.NewDecoder()
+		}
+
+		if len(op.args) == 2 {
+			to, err := getEncoding(op.args[1])
+			if err != nil {
+				return err
+			}
+
+			if to != nil {
+				op.encoder = to.NewEncoder()
+			}
+		}
+	}
+
+	return nil
+}
+
+//castNumber normalize the number to use . as decimal separator and no thousands separator.
+func castNumber(value string, decimal string, precision int) (string, error) {
+	thousands := ","
+	if decimal == "," {
+		thousands = "."
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		// Spaces, including the non-breaking ones, are used as thousands separator
+		if r == ' ' || r == '\u00a0' || r == '\u202f' || r == '\'' || string(r) == thousands {
+			return -1
+		}
+
+		if string(r) == decimal {
+			return '.'
+		}
+
+		return r
+	}, value)
+
+	if !castNumberRe.MatchString(cleaned) {
+		return "", fmt.Errorf("%s is not a number: %w", value, errWrongParameterValue)
+	}
+
+	r, ok := new(big.Rat).SetString(cleaned)
+	if !ok {
+		return "", fmt.Errorf("%s is not a number: %w", value, errWrongParameterValue)
+	}
+
+	if precision < 0 {
+		// Keep the number of decimals of the value
+		mantissa := strings.ToLower(cleaned)
+		exponent := 0
+
+		if i := strings.Index(mantissa, "e"); i >= 0 {
+			exponent, _ = strconv.Atoi(mantissa[i+1:])
+			mantissa = mantissa[:i]
+		}
+
+		if i := strings.Index(mantissa, "."); i >= 0 {
+			precision = len(mantissa) - i - 1
+		}
+
+		if precision -= exponent; precision < 0 {
+			precision = 0
+		}
+	}
+
+	return r.FloatString(precision), nil
+}
+
+//apply convert the value.
+func (op *castOperation) apply(value string) (string, error) {
+	switch op.name {
+	case "trim":
+		return strings.TrimSpace(value), nil
+	case "lower":
+		return strings.ToLower(value), nil
+	case "upper":
+		return strings.ToUpper(value), nil
+	case "nullifempty":
+		if value == "" {
+			return types.NullValue, nil
+		}
+
+		return value, nil
+	case "bool":
+		v := strings.ToLower(strings.TrimSpace(value))
+
+		if castTrueValues[v] {
+			return op.args[0], nil
+		}
+
+		if castFalseValues[v] {
+			return op.args[1], nil
+		}
+
+		return "", fmt.Errorf("%s is not a boolean: %w", value, errWrongParameterValue)
+	case "number":
+		return castNumber(strings.TrimSpace(value), op.args[0], op.precision)
+	case "date":
+		t, err := time.ParseInLocation(op.args[0], strings.TrimSpace(value), op.from)
+		if err != nil {
+			return "", err
+		}
+
+		return t.In(op.to).Format(op.args[1]), nil
+	case "charset":
+		var err error
+
+		if op.decoder != nil {
+			if value, err = op.decoder.String(value); err != nil {
+				return "", err
+			}
+		}
+
+		if op.encoder != nil {
+			if value, err = op.encoder.String(value); err != nil {
+				return "", err
+			}
+		}
+
+		return value, nil
+	}
+
+	return value, nil
+}
+
+//parseCastOperations parse a list of operations separated by | like `trim | date("02/01/2006", "2006-01-02")`.
+func parseCastOperations(spec string) ([]castOperation, error) {
+	calls, err := parseCalls(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]castOperation, 0, len(calls))
+
+	for _, c := range calls {
+		op := castOperation{call: c}
+		if err = op.prepare(); err != nil {
+			return nil, err
+		}
+
+		operations = append(operations, op)
+	}
+
+	return operations, nil
+}
+
+func newCastFilter(log *logrus.Entry, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "cast")
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to cast nothing")
+		return nil, fmt.Errorf("filter cast refuse to cast nothing: %w", errMissingParameter)
+	}
+
+	columns := make(map[string][]castOperation)
+
+	logFilter.Info("Will apply cast filter on:")
+
+	for name, value := range mParam {
+		operations, err := parseCastOperations(value)
+		if err != nil {
+			logFilter.Errorf("unable to parse the cast operations for %s (%s): %v", name, value, err)
+			return nil, fmt.Errorf("parsing %s provided: %w", name, err)
+		}
+
+		columns[name] = operations
+
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	return &CastFilter{columns: columns}, nil
+}
+
+// Filter : convert the columns by applying their operations in order (the NULL values and the columns not present are ignored).
+func (cf *CastFilter) Filter(in types.Record) ([]types.Record, error) {
+	out := make(types.Record, len(in))
+
+	for col, value := range in {
+		out[col] = value
+	}
+
+	for col, operations := range cf.columns {
+		value, ok := in[col]
+		if !ok {
+			continue
+		}
+
+		var err error
+
+		for _, op := range operations {
+			if value == types.NullValue {
+				break
+			}
+
+			if value, err = op.apply(value); err != nil {
+				return nil, fmt.Errorf("casting %s with %s: %w", col, op.name, err)
+			}
+		}
+
+		out[col] = value
+	}
+
+	return []types.Record{out}, nil
+}
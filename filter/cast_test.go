@@ -0,0 +1,95 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestFilterCastOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		spec     string
+		value    string
+		expected string
+	}{
+		{"trim", "  Pikachu ", "Pikachu"},
+		{"trim | lower", "  PIKACHU ", "pikachu"},
+		{"UPPER", "pikachu", "PIKACHU"},
+		{"trim | nullIfEmpty", "   ", types.NullValue},
+		{"nullIfEmpty | upper", types.NullValue, types.NullValue},
+		{"nullIfEmpty", "a", "a"},
+		{"bool", "Yes", "true"},
+		{"bool", "0", "false"},
+		{`bool("1", "0")`, "TRUE", "1"},
+		{`bool("Y", "N")`, " no ", "N"},
+		{"number", "1,234.50", "1234.50"},
+		{`number(",")`, "1 234,5", "1234.5"},
+		{`number(",", 2)`, "1.234,567", "1234.57"},
+		{`number(".", 0)`, "-2.5", "-3"},
+		{`number(".", 3)`, "42", "42.000"},
+		{"number", "1.5e3", "1500"},
+		{"number", "+.25", "0.25"},
+		{"number", "123456789012345678901234567890", "123456789012345678901234567890"},
+		{`date("02/01/2006", "2006-01-02")`, "27/02/1996", "1996-02-27"},
+		{`date("02/01/2006 15:04", "2006-01-02T15:04:05Z07:00", "Europe/Paris", "UTC")`, "27/02/1996 10:30", "1996-02-27T09:30:00Z"},
+		{`date("2006-01-02T15:04:05Z07:00", "2006-01-02 15:04:05", "UTC", "Asia/Tokyo")`, "1996-02-27T09:30:00+02:00", "1996-02-27 16:30:00"},
+		{`charset("iso-8859-1")`, "Pok\xe9mon", "Pokémon"},
+		{`charset("utf-8", "windows-1252")`, "Pokémon", "Pok\xe9mon"},
+	}
+
+	for _, test := range tests {
+		f, err := filter.NewFilter(log, "cast", nil, map[string]string{"col": test.spec})
+		if err != nil {
+			t.Fatalf("NewFilter should not returns an error for %s, returned: %v", test.spec, err)
+		}
+
+		out, err := filterOne(t, f, types.Record{"col": test.value, "other": " x "})
+		if err != nil {
+			t.Fatalf("Filter should not returns an error for %s on %s, returned: %v", test.spec, test.value, err)
+		}
+
+		if out["col"] != test.expected {
+			t.Errorf("%s on '%s' should give '%s', it gives '%s'", test.spec, test.value, test.expected, out["col"])
+		}
+
+		if out["other"] != " x " {
+			t.Errorf("The columns not listed should not be modified, it is '%s'", out["other"])
+		}
+	}
+}
+
+func TestFilterCastFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "cast", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter cast without parameters should returns an error")
+	}
+
+	for _, spec := range []string{"", "unknown", "trim |", "trim lower", "trim(1)", `bool("Y")`, `number(";")`, `number(".", "x")`,
+		`date("2006")`, `date("2006", "2006", "Nowhere/Unknown")`, `charset("unknown")`, `date("2006" "2006")`, "date(trim)"} {
+		_, err = filter.NewFilter(log, "cast", nil, map[string]string{"col": spec})
+		if err == nil {
+			t.Errorf("NewFilter cast with wrong operations '%s' should returns an error", spec)
+		}
+	}
+
+	for spec, value := range map[string]string{"bool": "maybe", "number": "12a", `number(",")`: "1,2,3", `date("02/01/2006", "2006-01-02")`: "1996-02-27"} {
+		f, err := filter.NewFilter(log, "cast", nil, map[string]string{"col": spec})
+		if err != nil {
+			t.Fatalf("NewFilter should not returns an error for %s, returned: %v", spec, err)
+		}
+
+		if _, err = f.Filter(types.Record{"col": value}); err == nil {
+			t.Errorf("%s on '%s' should returns an error", spec, value)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"os"
+	"strings"
 
 	"github.com/Masterminds/sprig/v3"
 )
@@ -12,6 +14,18 @@ type tmplEnv struct {
 	Environments map[string]string
 }
 
+//newTmplEnv return the values available in the templates of the filter parameters.
+func newTmplEnv() tmplEnv {
+	envVar := make(map[string]string)
+
+	for _, v := range os.Environ() {
+		splitV := strings.SplitN(v, "=", 2)
+		envVar[splitV[0]] = splitV[1]
+	}
+
+	return tmplEnv{Environments: envVar}
+}
+
 func parseField(fieldName string, fieldValue string, data tmplEnv) (string, error) {
 	var buf bytes.Buffer
 
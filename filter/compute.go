@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"bytes"
+	"crypto/md5" //nolint: gosec
+	"crypto/sha512"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// ComputeFilter specific type for compute filter operation.
+type ComputeFilter struct {
+	templates map[string]*template.Template
+}
+
+//computeFuncs return the functions available in the compute templates in addition of the sprig ones.
+func computeFuncs() template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+
+	funcs["md5sum"] = func(value string) string {
+		return fmt.Sprintf("%x", md5.Sum([]byte(value))) //nolint: gosec
+	}
+	funcs["sha512sum"] = func(value string) string {
+		return fmt.Sprintf("%x", sha512.Sum512([]byte(value)))
+	}
+	// reformatDate convert a date from a Golang layout to another
+	funcs["reformatDate"] = func(from string, to string, value string) (string, error) {
+		t, err := time.Parse(from, value)
+		if err != nil {
+			return "", err
+		}
+
+		return t.Format(to), nil
+	}
+	funcs["null"] = func() string {
+		return types.NullValue
+	}
+	funcs["isNull"] = func(value string) bool {
+		return value == types.NullValue
+	}
+
+	return funcs
+}
+
+func newComputeFilter(log *logrus.Entry, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "compute")
+
+	if mParam == nil {
+		logFilter.Error("Missing MParameters")
+		return nil, fmt.Errorf("no parameter to filter compute: %w", errMissingParameter)
+	}
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to compute nothing")
+		return nil, fmt.Errorf("filter compute refuse to compute nothing: %w", errWrongParameterValue)
+	}
+
+	templates := make(map[string]*template.Template)
+	funcs := computeFuncs()
+
+	logFilter.Info("Will apply compute filter on:")
+
+	for name, value := range mParam {
+		tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(value)
+		if err != nil {
+			logFilter.Errorf("unable to parse the template for %s (%s): %v", name, value, err)
+			return nil, fmt.Errorf("parsing %s provided: %w", name, err)
+		}
+
+		templates[name] = tmpl
+
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	return &ComputeFilter{templates: templates}, nil
+}
+
+// Filter : set the columns with the result of their template evaluated on the record (insert the column if not present).
+func (cf *ComputeFilter) Filter(in types.Record) ([]types.Record, error) {
+	out := make(types.Record, len(in))
+
+	for col, value := range in {
+		out[col] = value
+	}
+
+	// The templates are all evaluated on the input record, so the result does not depend on their order
+	for col, tmpl := range cf.templates {
+		var buf bytes.Buffer
+
+		if err := tmpl.Execute(&buf, map[string]string(in)); err != nil {
+			return nil, fmt.Errorf("computing %s: %w", col, err)
+		}
+
+		out[col] = buf.String()
+	}
+
+	return []types.Record{out}, nil
+}
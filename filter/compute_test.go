@@ -0,0 +1,78 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestFilterComputeOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	mParams := map[string]string{
+		"slug":     "{{ .name | lower }}-{{ .id }}",
+		"name":     "{{ .name | upper }}",
+		"birth":    `{{ reformatDate "02/01/2006" "2006-01-02" .birth }}`,
+		"checksum": "{{ .name | md5sum }}",
+		"comment":  "{{ if isNull .comment }}none{{ else }}{{ .comment }}{{ end }}",
+		"removed":  "{{ null }}",
+		"missing":  "[{{ .unknown }}]",
+	}
+
+	f, err := filter.NewFilter(log, "compute", nil, mParams)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	out, err := filterOne(t, f, types.Record{"id": "25", "name": "Pikachu", "birth": "27/02/1996", "comment": types.NullValue})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	expected := map[string]string{
+		"id":       "25",
+		"slug":     "pikachu-25",
+		"name":     "PIKACHU",
+		"birth":    "1996-02-27",
+		"checksum": "4b576e26f68e1a0a5792019088bd0442",
+		"comment":  "none",
+		"removed":  types.NullValue,
+		"missing":  "[]",
+	}
+
+	for col, value := range expected {
+		if out[col] != value {
+			t.Errorf("The column %s should be '%s', it is '%s'", col, value, out[col])
+		}
+	}
+}
+
+func TestFilterComputeFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "compute", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter compute without parameters should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "compute", nil, map[string]string{"slug": "{{ .name "})
+	if err == nil {
+		t.Errorf("NewFilter compute with wrong template should returns an error")
+	}
+
+	f, err := filter.NewFilter(log, "compute", nil, map[string]string{"birth": `{{ reformatDate "02/01/2006" "2006-01-02" .birth }}`})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	_, err = filterOne(t, f, types.Record{"birth": "not a date"})
+	if err == nil {
+		t.Errorf("Filter with wrong date should returns an error")
+	}
+}
@@ -0,0 +1,174 @@
+package filter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// CryptFilter specific type for encrypt and decrypt filter operations.
+type CryptFilter struct {
+	aead    cipher.AEAD
+	columns []string
+	encrypt bool
+}
+
+//decodeKey return the AES key from its base64 or hexadecimal representation.
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	for _, decode := range []func(string) ([]byte, error){base64.StdEncoding.DecodeString, hex.DecodeString} {
+		key, err := decode(encoded)
+		if err == nil && (len(key) == 16 || len(key) == 24 || len(key) == 32) {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the key must be a base64 or hexadecimal encoded 128, 192 or 256 bits key: %w", errWrongParameterValue)
+}
+
+//readKey return the key from the environment variable or the file, the key itself is never in the step file.
+func readKey(mParam map[string]string) ([]byte, error) {
+	for name := range mParam {
+		if name != "keyenv" && name != "keyfile" {
+			return nil, fmt.Errorf("unknown parameter %s, the key can only be provided by keyEnv or keyFile: %w", name, errWrongParameterValue)
+		}
+	}
+
+	env, file := mParam["keyenv"], mParam["keyfile"]
+
+	switch {
+	case env != "" && file != "":
+		return nil, fmt.Errorf("the key can not be provided by keyEnv and keyFile: %w", errWrongParameterValue)
+	case env != "":
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s not set: %w", env, errMissingParameter)
+		}
+
+		return decodeKey(value)
+	case file != "":
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeKey(string(content))
+	}
+
+	return nil, fmt.Errorf("no key provided by keyEnv or keyFile: %w", errMissingParameter)
+}
+
+func newCryptFilter(log *logrus.Entry, encrypt bool, aParam []string, mParam map[string]string) (Filter, error) {
+	name := "decrypt"
+	if encrypt {
+		name = "encrypt"
+	}
+
+	logFilter := log.WithField("filter", name)
+
+	if len(aParam) == 0 {
+		logFilter.Error("Missing columns in AParameters")
+		return nil, fmt.Errorf("no column provided to filter %s: %w", name, errMissingParameter)
+	}
+
+	// Viper does not keep the case of the keys, the parameters are compared in lowercase
+	params := make(map[string]string)
+	for k, v := range mParam {
+		params[strings.ToLower(k)] = v
+	}
+
+	key, err := readKey(params)
+	if err != nil {
+		logFilter.Errorf("Unable to get the key: %v", err)
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	logFilter.Infof("Will apply %s filter on:", name)
+
+	for _, col := range aParam {
+		logFilter.Infof("   - %s", col)
+	}
+
+	return &CryptFilter{aead: aead, columns: aParam, encrypt: encrypt}, nil
+}
+
+//seal encrypt the value with a random nonce and return the base64 encoding of the nonce followed by the ciphertext.
+func (cf *CryptFilter) seal(value string) (string, error) {
+	nonce := make([]byte, cf.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(cf.aead.Seal(nonce, nonce, []byte(value), nil)), nil
+}
+
+//open decrypt a value encrypted by seal.
+func (cf *CryptFilter) open(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < cf.aead.NonceSize() {
+		return "", fmt.Errorf("encrypted value too short: %w", errWrongParameterValue)
+	}
+
+	plain, err := cf.aead.Open(nil, data[:cf.aead.NonceSize()], data[cf.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// Filter : encrypt or decrypt the columns (the NULL values and the columns not present are ignored).
+func (cf *CryptFilter) Filter(in types.Record) ([]types.Record, error) {
+	out := make(types.Record, len(in))
+
+	for col, value := range in {
+		out[col] = value
+	}
+
+	for _, col := range cf.columns {
+		value, ok := in[col]
+		if !ok || value == types.NullValue {
+			continue
+		}
+
+		var err error
+
+		if cf.encrypt {
+			value, err = cf.seal(value)
+		} else {
+			value, err = cf.open(value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col, err)
+		}
+
+		out[col] = value
+	}
+
+	return []types.Record{out}, nil
+}
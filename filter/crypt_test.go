@@ -0,0 +1,121 @@
+package filter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+const (
+	testKeyBase64 = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testKeyHex    = "000102030405060708090a0b0c0d0e0f"
+)
+
+func TestFilterCryptOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	os.Setenv("KAMINO_TEST_CRYPT_KEY", testKeyBase64)
+	defer os.Unsetenv("KAMINO_TEST_CRYPT_KEY")
+
+	encrypt, err := filter.NewFilter(log, "encrypt", []string{"ssn", "email", "missing"}, map[string]string{"keyEnv": "KAMINO_TEST_CRYPT_KEY"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	keyFile := filepath.Join(os.TempDir(), "kamino_test_crypt.key")
+	if err = ioutil.WriteFile(keyFile, []byte(testKeyBase64+"\n"), 0600); err != nil {
+		t.Fatalf("Writing the key file failed: %v", err)
+	}
+	defer os.Remove(keyFile)
+
+	decrypt, err := filter.NewFilter(log, "decrypt", []string{"ssn", "email"}, map[string]string{"keyFile": keyFile})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	in := types.Record{"id": "1", "ssn": "123-45-6789", "email": types.NullValue}
+
+	encrypted, err := filterOne(t, encrypt, in)
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if encrypted["ssn"] == in["ssn"] || encrypted["id"] != "1" || encrypted["email"] != types.NullValue {
+		t.Errorf("Only the ssn should be encrypted, the record is %v", encrypted)
+	}
+
+	if _, ok := encrypted["missing"]; ok {
+		t.Errorf("The columns not present should not be added")
+	}
+
+	again, _ := filterOne(t, encrypt, in)
+	if again["ssn"] == encrypted["ssn"] {
+		t.Errorf("Each encryption should use a different nonce")
+	}
+
+	decrypted, err := filterOne(t, decrypt, encrypted)
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	for col, value := range in {
+		if decrypted[col] != value {
+			t.Errorf("The decrypted %s should be %s, it is %s", col, value, decrypted[col])
+		}
+	}
+}
+
+func TestFilterCryptFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	os.Setenv("KAMINO_TEST_CRYPT_KEY", testKeyBase64)
+	defer os.Unsetenv("KAMINO_TEST_CRYPT_KEY")
+	os.Setenv("KAMINO_TEST_CRYPT_OTHER", testKeyHex)
+	defer os.Unsetenv("KAMINO_TEST_CRYPT_OTHER")
+	os.Setenv("KAMINO_TEST_CRYPT_WRONG", "not a key")
+	defer os.Unsetenv("KAMINO_TEST_CRYPT_WRONG")
+
+	for _, mParams := range []map[string]string{
+		nil,
+		{"key": testKeyBase64},
+		{"keyEnv": "KAMINO_TEST_CRYPT_UNSET"},
+		{"keyEnv": "KAMINO_TEST_CRYPT_WRONG"},
+		{"keyFile": "testdata/unknown.key"},
+		{"keyEnv": "KAMINO_TEST_CRYPT_KEY", "keyFile": "testdata/unknown.key"},
+	} {
+		if _, err := filter.NewFilter(log, "encrypt", []string{"ssn"}, mParams); err == nil {
+			t.Errorf("NewFilter encrypt with %v should returns an error", mParams)
+		}
+	}
+
+	if _, err := filter.NewFilter(log, "encrypt", nil, map[string]string{"keyEnv": "KAMINO_TEST_CRYPT_KEY"}); err == nil {
+		t.Errorf("NewFilter encrypt without columns should returns an error")
+	}
+
+	encrypt, err := filter.NewFilter(log, "encrypt", []string{"ssn"}, map[string]string{"keyEnv": "KAMINO_TEST_CRYPT_KEY"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	decrypt, err := filter.NewFilter(log, "decrypt", []string{"ssn"}, map[string]string{"keyEnv": "KAMINO_TEST_CRYPT_OTHER"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	encrypted, _ := filterOne(t, encrypt, types.Record{"ssn": "123-45-6789"})
+
+	for _, value := range []string{encrypted["ssn"], "not base64", "c2hvcnQ="} {
+		if _, err = decrypt.Filter(types.Record{"ssn": value}); err == nil {
+			t.Errorf("Filter decrypt of %s should returns an error", value)
+		}
+	}
+}
@@ -2,7 +2,22 @@ package filter
 
 import (
 	"errors"
+	"fmt"
+
+	"github.com/marema31/kamino/provider/types"
 )
 
 var errMissingParameter = errors.New("MISSING PARAMETER")
 var errWrongParameterValue = errors.New("WRONG PARAMETER VALUE")
+var errNotFound = errors.New("NOT FOUND")
+
+//RejectError is returned by a filter for a record that must be written to the rejects datasource instead of stopping the synchronization.
+type RejectError struct {
+	Filter string
+	Reason string
+	Record types.Record
+}
+
+func (e *RejectError) Error() string {
+	return fmt.Sprintf("rejected by %s filter: %s", e.Filter, e.Reason)
+}
@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// ExplodeFilter specific type for explode filter operation.
+type ExplodeFilter struct {
+	columns    []string
+	separators map[string]string
+}
+
+func newExplodeFilter(log *logrus.Entry, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "explode")
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to explode nothing")
+		return nil, fmt.Errorf("filter explode refuse to explode nothing: %w", errMissingParameter)
+	}
+
+	columns := make([]string, 0, len(mParam))
+	separators := make(map[string]string)
+
+	logFilter.Info("Will apply explode filter on:")
+
+	for name, value := range mParam {
+		if value == "" {
+			logFilter.Errorf("Empty separator for %s", name)
+			return nil, fmt.Errorf("empty separator provided to filter explode for %s: %w", name, errWrongParameterValue)
+		}
+
+		columns = append(columns, name)
+		separators[name] = value
+
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	// The order of the generated records must not depend on the order of the map
+	sort.Strings(columns)
+
+	return &ExplodeFilter{columns: columns, separators: separators}, nil
+}
+
+//split return the trimmed non empty parts of the value.
+func split(value string, separator string) []string {
+	parts := make([]string, 0)
+
+	for _, part := range strings.Split(value, separator) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return parts
+}
+
+//Exploded return the columns having a different value in each generated record.
+func (ef *ExplodeFilter) Exploded() []string {
+	return ef.columns
+}
+
+// Filter : generate a record by element of the delimited columns (all the combinations if several columns are exploded).
+func (ef *ExplodeFilter) Filter(in types.Record) ([]types.Record, error) {
+	records := []types.Record{in}
+
+	for _, col := range ef.columns {
+		value, ok := in[col]
+		if !ok || value == types.NullValue {
+			continue
+		}
+
+		parts := split(value, ef.separators[col])
+		// A column without element is kept as is to not lose the record
+		if len(parts) == 0 {
+			continue
+		}
+
+		next := make([]types.Record, 0, len(records)*len(parts))
+
+		for _, record := range records {
+			for _, part := range parts {
+				out := make(types.Record, len(record))
+
+				for c, v := range record {
+					out[c] = v
+				}
+
+				out[col] = part
+				next = append(next, out)
+			}
+		}
+
+		records = next
+	}
+
+	return records, nil
+}
@@ -0,0 +1,93 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestFilterExplodeOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	f, err := filter.NewFilter(log, "explode", nil, map[string]string{"types": ",", "games": "|"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	out, err := f.Filter(types.Record{"name": "Lugia", "types": "psychic, flying,", "games": "Gold|Silver"})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	expected := [][2]string{{"Gold", "psychic"}, {"Gold", "flying"}, {"Silver", "psychic"}, {"Silver", "flying"}}
+	if len(out) != len(expected) {
+		t.Fatalf("The filter should return %d records, returned: %v", len(expected), out)
+	}
+
+	for i, e := range expected {
+		if out[i]["name"] != "Lugia" || out[i]["games"] != e[0] || out[i]["types"] != e[1] {
+			t.Errorf("The record %d should be %v, it is %v", i, e, out[i])
+		}
+	}
+
+	for _, in := range []types.Record{{"name": "Ditto"}, {"name": "Ditto", "types": ""}, {"name": "Ditto", "types": types.NullValue}} {
+		out, err = f.Filter(in)
+		if err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+
+		if len(out) != 1 || out[0]["types"] != in["types"] {
+			t.Errorf("The record %v should be kept as is, filter returned %v", in, out)
+		}
+	}
+}
+
+func TestFilterExplodeFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "explode", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter explode without parameters should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "explode", nil, map[string]string{"types": ""})
+	if err == nil {
+		t.Errorf("NewFilter explode with empty separator should returns an error")
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	explode, err := filter.NewFilter(log, "explode", nil, map[string]string{"types": ","})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	where, err := filter.NewFilter(log, "where", []string{`types != "flying"`}, nil)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	out, _, err := filter.Apply([]filter.Filter{explode, where}, types.Record{"name": "Lugia", "types": "psychic,flying"})
+	if err != nil {
+		t.Fatalf("Apply should not returns an error, returned: %v", err)
+	}
+
+	if len(out) != 1 || out[0]["types"] != "psychic" {
+		t.Errorf("Each record produced by a filter should be given to the next one, returned %v", out)
+	}
+
+	out, _, err = filter.Apply(nil, types.Record{"name": "Lugia"})
+	if err != nil || len(out) != 1 || out[0]["name"] != "Lugia" {
+		t.Errorf("Apply without filter should return the record, returned %v, %v", out, err)
+	}
+}
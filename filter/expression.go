@@ -0,0 +1,522 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Kinds of token of the predicate expressions.
+const (
+	tokEnd = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOperator
+)
+
+type token struct {
+	kind  int
+	value string
+}
+
+// Operators recognized by the lexer (also used to parse the calls), the longest first.
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "!~", "<", ">", "!", "(", ")", "|", ","}
+
+//exprValue is the result of the evaluation of an operand.
+type exprValue struct {
+	value  string
+	null   bool
+	isBool bool
+	bool   bool
+}
+
+//expression is a node of the syntax tree of a predicate.
+type expression interface {
+	eval(types.Record) exprValue
+}
+
+type literalExpr struct {
+	v exprValue
+}
+
+type columnExpr struct {
+	name string
+}
+
+type notExpr struct {
+	e expression
+}
+
+type logicalExpr struct {
+	op          string
+	left, right expression
+}
+
+type compareExpr struct {
+	op          string
+	left, right expression
+	re          *regexp.Regexp
+}
+
+func (e *literalExpr) eval(types.Record) exprValue {
+	return e.v
+}
+
+func (e *columnExpr) eval(record types.Record) exprValue {
+	value, ok := record[e.name]
+	if !ok || value == types.NullValue {
+		return exprValue{null: true}
+	}
+
+	return exprValue{value: value}
+}
+
+func (e *notExpr) eval(record types.Record) exprValue {
+	return boolValue(!truth(e.e.eval(record)))
+}
+
+func (e *logicalExpr) eval(record types.Record) exprValue {
+	left := truth(e.left.eval(record))
+
+	// Short-circuit evaluation
+	if e.op == "&&" && !left {
+		return boolValue(false)
+	}
+
+	if e.op == "||" && left {
+		return boolValue(true)
+	}
+
+	return boolValue(truth(e.right.eval(record)))
+}
+
+func (e *compareExpr) eval(record types.Record) exprValue {
+	left := e.left.eval(record)
+
+	if e.re != nil {
+		if left.null {
+			return boolValue(false)
+		}
+
+		return boolValue(e.re.MatchString(left.value) == (e.op == "=~"))
+	}
+
+	right := e.right.eval(record)
+
+	// NULL is only equal to NULL and can not be ordered
+	if left.null || right.null {
+		switch e.op {
+		case "==":
+			return boolValue(left.null && right.null)
+		case "!=":
+			return boolValue(left.null != right.null)
+		default:
+			return boolValue(false)
+		}
+	}
+
+	var cmp int
+
+	switch {
+	case left.isBool || right.isBool:
+		if truth(left) == truth(right) {
+			cmp = 0
+		} else {
+			cmp = 1
+		}
+	default:
+		cmp = compareValues(left.value, right.value)
+	}
+
+	switch e.op {
+	case "==":
+		return boolValue(cmp == 0)
+	case "!=":
+		return boolValue(cmp != 0)
+	case "<":
+		return boolValue(cmp < 0)
+	case "<=":
+		return boolValue(cmp <= 0)
+	case ">":
+		return boolValue(cmp > 0)
+	default:
+		return boolValue(cmp >= 0)
+	}
+}
+
+func boolValue(b bool) exprValue {
+	return exprValue{isBool: true, bool: b}
+}
+
+//truth return the boolean value of an operand, a column is true if its content is a true boolean (true, 1, ...).
+func truth(v exprValue) bool {
+	if v.isBool {
+		return v.bool
+	}
+
+	if v.null {
+		return false
+	}
+
+	b, err := strconv.ParseBool(strings.TrimSpace(v.value))
+
+	return err == nil && b
+}
+
+//compareValues compare numerically if the two values are numbers, as string otherwise.
+func compareValues(a, b string) int {
+	fa, erra := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errb := strconv.ParseFloat(strings.TrimSpace(b), 64)
+
+	if erra == nil && errb == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+//tokenize split the expression in tokens.
+func tokenize(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'' || r == '`':
+			// Strings are quoted by " or ', columns with unusual names by `
+			var sb strings.Builder
+
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				// Only the quote and the backslash are escaped, the other backslashes are kept for the regular expressions
+				if runes[j] == '\\' && j+1 < len(runes) && (runes[j+1] == r || runes[j+1] == '\\') {
+					j++
+				}
+
+				sb.WriteRune(runes[j])
+			}
+
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated %c in %s: %w", r, expr, errWrongParameterValue)
+			}
+
+			kind := tokString
+			if r == '`' {
+				kind = tokIdent
+			}
+
+			tokens = append(tokens, token{kind: kind, value: sb.String()})
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokNumber, value: string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '.') {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokIdent, value: string(runes[i:j])})
+			i = j
+		default:
+			found := false
+
+			for _, op := range exprOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokOperator, value: op})
+					i += len([]rune(op))
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("unexpected character %c in %s: %w", r, expr, errWrongParameterValue)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokEnd}), nil
+}
+
+//exprParser is a recursive descent parser of the predicate expressions.
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *exprParser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOperator {
+		return false
+	}
+
+	for _, op := range ops {
+		if t.value == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *exprParser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("||") {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalExpr{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("&&") {
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalExpr{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (expression, error) {
+	if p.isOperator("!") {
+		p.next()
+
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notExpr{e: e}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isOperator("==", "!=", "<", "<=", ">", ">=", "=~", "!~") {
+		return left, nil
+	}
+
+	op := p.next().value
+
+	if op == "=~" || op == "!~" {
+		t := p.next()
+		if t.kind != tokString {
+			return nil, fmt.Errorf("operator %s needs a quoted regular expression: %w", op, errWrongParameterValue)
+		}
+
+		re, err := regexp.Compile(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("regular expression %s: %w", t.value, err)
+		}
+
+		return &compareExpr{op: op, left: left, re: re}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *exprParser) parseOperand() (expression, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokString, tokNumber:
+		return &literalExpr{v: exprValue{value: t.value}}, nil
+	case tokIdent:
+		switch t.value {
+		case "null":
+			return &literalExpr{v: exprValue{null: true}}, nil
+		case "true", "false":
+			return &literalExpr{v: boolValue(t.value == "true")}, nil
+		}
+
+		return &columnExpr{name: t.value}, nil
+	case tokOperator:
+		if t.value == "(" {
+			e, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			if !p.isOperator(")") {
+				return nil, fmt.Errorf("missing closing parenthesis: %w", errWrongParameterValue)
+			}
+
+			p.next()
+
+			return e, nil
+		}
+
+		return nil, fmt.Errorf("unexpected operator %s: %w", t.value, errWrongParameterValue)
+	default:
+		return nil, fmt.Errorf("unexpected end of expression: %w", errWrongParameterValue)
+	}
+}
+
+//parseExpression compile a predicate expression.
+func parseExpression(expr string) (expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokEnd {
+		return nil, fmt.Errorf("unexpected %s at the end of %s: %w", t.value, expr, errWrongParameterValue)
+	}
+
+	return e, nil
+}
+
+//call is an operation with its arguments, like `date("02/01/2006", "2006-01-02")`.
+type call struct {
+	name string
+	args []string
+}
+
+//String return the call as written in the step file (with the name in lowercase).
+func (c call) String() string {
+	if len(c.args) == 0 {
+		return c.name
+	}
+
+	args := make([]string, 0, len(c.args))
+	for _, arg := range c.args {
+		args = append(args, strconv.Quote(arg))
+	}
+
+	return fmt.Sprintf("%s(%s)", c.name, strings.Join(args, ", "))
+}
+
+//parseCalls parse a list of calls separated by |, their names are returned in lowercase.
+func parseCalls(spec string) ([]call, error) {
+	tokens, err := tokenize(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	calls := make([]call, 0)
+
+	for {
+		t := p.next()
+		if t.kind != tokIdent {
+			return nil, fmt.Errorf("operation expected in %s: %w", spec, errWrongParameterValue)
+		}
+
+		c := call{name: strings.ToLower(t.value), args: make([]string, 0)}
+
+		if p.isOperator("(") {
+			p.next()
+
+			for !p.isOperator(")") {
+				if p.peek().kind == tokEnd {
+					return nil, fmt.Errorf("missing closing parenthesis of %s in %s: %w", c.name, spec, errWrongParameterValue)
+				}
+
+				if len(c.args) > 0 {
+					if !p.isOperator(",") {
+						return nil, fmt.Errorf("missing , between the arguments of %s: %w", c.name, errWrongParameterValue)
+					}
+
+					p.next()
+				}
+
+				arg := p.next()
+				if arg.kind != tokString && arg.kind != tokNumber {
+					return nil, fmt.Errorf("wrong argument of %s in %s: %w", c.name, spec, errWrongParameterValue)
+				}
+
+				c.args = append(c.args, arg.value)
+			}
+
+			p.next()
+		}
+
+		calls = append(calls, c)
+
+		if p.peek().kind == tokEnd {
+			return calls, nil
+		}
+
+		if !p.isOperator("|") {
+			return nil, fmt.Errorf("missing | between the operations of %s: %w", spec, errWrongParameterValue)
+		}
+
+		p.next()
+	}
+}
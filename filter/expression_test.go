@@ -0,0 +1,93 @@
+package filter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+)
+
+func TestExpressionErrors(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		predicate string
+		message   string
+	}{
+		{`name == "Pika`, `unterminated "`},
+		{`name == 'Pika`, `unterminated '`},
+		{"`first name == 1", "unterminated `"},
+		{`name == "Pika\"`, `unterminated "`},
+		{`a == 1)`, "unexpected ) at the end"},
+		{`)`, "unexpected operator )"},
+		{`(a == 1`, "missing closing parenthesis"},
+		{`a ==`, "unexpected end of expression"},
+		{`a == 1 &&`, "unexpected end of expression"},
+		{`!`, "unexpected end of expression"},
+		{`a == 1 b`, "unexpected b at the end"},
+		{`a # 1`, "unexpected character #"},
+		{`a =~ b`, "needs a quoted regular expression"},
+		{`a !~ 1`, "needs a quoted regular expression"},
+		{`a =~ "("`, "regular expression ("},
+		{`a =~ "[a-"`, "regular expression [a-"},
+	}
+
+	for _, test := range tests {
+		_, err := filter.NewFilter(log, "where", []string{test.predicate}, nil)
+		if err == nil {
+			t.Errorf("NewFilter where with wrong predicate '%s' should returns an error", test.predicate)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.message) {
+			t.Errorf("The error for '%s' should contain '%s', it is: %v", test.predicate, test.message, err)
+		}
+	}
+}
+
+func TestCallsErrors(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		spec    string
+		message string
+	}{
+		{`date("2006" "2006")`, "missing , between the arguments of date"},
+		{`date("2006",, "2006")`, "wrong argument of date"},
+		{`date(trim)`, "wrong argument of date"},
+		{`date("2006"`, "missing closing parenthesis of date"},
+		{`date(`, "missing closing parenthesis of date"},
+		{`date("2006`, `unterminated "`},
+		{`trim lower`, "missing | between the operations"},
+		{`trim upper(1) lower`, "missing | between the operations"},
+		{`trim |`, "operation expected"},
+		{`| trim`, "operation expected"},
+		{`"trim"`, "operation expected"},
+		{`trim # lower`, "unexpected character #"},
+	}
+
+	for _, test := range tests {
+		_, err := filter.NewFilter(log, "cast", nil, map[string]string{"col": test.spec})
+		if err == nil {
+			t.Errorf("NewFilter cast with wrong operations '%s' should returns an error", test.spec)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.message) {
+			t.Errorf("The error for '%s' should contain '%s', it is: %v", test.spec, test.message, err)
+		}
+	}
+
+	// The calls of validate are parsed the same way
+	for spec, message := range map[string]string{`regex("(")`: "regular expression (", `regex("a" "b")`: "missing , between the arguments of regex", "required enum": "missing | between the operations"} {
+		_, err := filter.NewFilter(log, "validate", nil, map[string]string{"col": spec})
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("The error of validate for '%s' should contain '%s', it is: %v", spec, message, err)
+		}
+	}
+}
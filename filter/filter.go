@@ -1,15 +1,26 @@
 package filter
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/provider/types"
 )
 
-//Filter provides way to filter record by record.
+//Filter provides way to filter record by record, a record can give zero, one or several records.
 type Filter interface {
-	Filter(types.Record) (types.Record, error)
+	Filter(types.Record) ([]types.Record, error)
+}
+
+//Rejecter is implemented by the filters able to reject records to the rejects datasource of the step.
+type Rejecter interface {
+	Rejects() bool
+}
+
+//Exploder is implemented by the filters generating several records from one, they return the columns that differ between these records.
+type Exploder interface {
+	Exploded() []string
 }
 
 //NewFilter analyze the config map and return object implemnting Filter of the asked type.
@@ -21,8 +32,56 @@ func NewFilter(log *logrus.Entry, filterType string, aParam []string, mParam map
 		return newReplaceFilter(log, mParam)
 	case "only":
 		return newOnlyFilter(log, aParam)
+	case "mask":
+		return newMaskFilter(log, aParam, mParam)
+	case "compute":
+		return newComputeFilter(log, mParam)
+	case "cast":
+		return newCastFilter(log, mParam)
+	case "script":
+		return newScriptFilter(log, aParam, mParam)
+	case "encrypt":
+		return newCryptFilter(log, true, aParam, mParam)
+	case "decrypt":
+		return newCryptFilter(log, false, aParam, mParam)
+	case "validate":
+		return newValidateFilter(log, aParam, mParam)
+	case "where":
+		return newWhereFilter(log, aParam)
+	case "explode":
+		return newExplodeFilter(log, mParam)
 	default:
 		log.Errorf("Don't know how to filter %s", filterType)
 		return nil, fmt.Errorf("don't know how to filter %s: %w", filterType, errWrongParameterValue)
 	}
 }
+
+//Apply chains the filters on the record, each record produced by a filter is given to the next one. The records rejected by a filter are returned separately.
+func Apply(filters []Filter, record types.Record) ([]types.Record, []*RejectError, error) {
+	records := []types.Record{record}
+	rejects := make([]*RejectError, 0)
+
+	for _, f := range filters {
+		next := make([]types.Record, 0, len(records))
+
+		for _, r := range records {
+			out, err := f.Filter(r)
+
+			var reject *RejectError
+			if errors.As(err, &reject) {
+				rejects = append(rejects, reject)
+				continue
+			}
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			next = append(next, out...)
+		}
+
+		records = next
+	}
+
+	return records, rejects, nil
+}
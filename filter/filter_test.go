@@ -36,7 +36,7 @@ func TestFilterOnlyOk(t *testing.T) {
 	in["id"] = "1"
 	in["name"] = "Doe"
 	in["firstname"] = "John"
-	out, err := f.Filter(in)
+	out, err := filterOne(t, f, in)
 	if err != nil {
 		t.Errorf("Filter should not returns an error, returned: %v", err)
 	}
@@ -101,7 +101,7 @@ func TestFilterReplaceOk(t *testing.T) {
 	in["id"] = "1"
 	in["name"] = "Doe"
 	in["firstname"] = "John"
-	out, err := f.Filter(in)
+	out, err := filterOne(t, f, in)
 	if err != nil {
 		t.Errorf("Filter should not returns an error, returned: %v", err)
 	}
@@ -154,3 +154,19 @@ func TestFilterReplaceFail(t *testing.T) {
 		t.Errorf("NewFilter replace without parameters should returns an error")
 	}
 }
+
+//filterOne apply the filter on the record and return the only record produced.
+func filterOne(t *testing.T, f filter.Filter, in types.Record) (types.Record, error) {
+	t.Helper()
+
+	out, err := f.Filter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) != 1 {
+		t.Fatalf("The filter should return exactly one record, it returned %d", len(out))
+	}
+
+	return out[0], nil
+}
@@ -0,0 +1,167 @@
+package filter
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Number of lookup results kept in memory if not provided.
+const defaultLookupCacheSize = 10000
+
+//LookupFunc return the record of the lookup datasource having this key value, nil if there is none.
+type LookupFunc func(value string) (types.Record, error)
+
+//LookupOptions describes how the lookup filter uses the records found.
+type LookupOptions struct {
+	Column    string // Column of the record containing the key value
+	Mode      string // replace or merge
+	Miss      string // keep, null, drop or fail
+	CacheSize int
+}
+
+//lookupEntry is an element of the cache of the lookup filter.
+type lookupEntry struct {
+	value  string
+	record types.Record
+}
+
+// LookupFilter specific type for lookup filter operation.
+type LookupFilter struct {
+	lookup  LookupFunc
+	options LookupOptions
+	columns map[string]string
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+//NewLookupFilter return a filter setting the columns of the record with the ones of the record of another datasource having the same key.
+func NewLookupFilter(log *logrus.Entry, lookup LookupFunc, options LookupOptions, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "lookup")
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to lookup nothing")
+		return nil, fmt.Errorf("filter lookup refuse to lookup nothing: %w", errMissingParameter)
+	}
+
+	if options.Column == "" {
+		logFilter.Error("Missing key column")
+		return nil, fmt.Errorf("no key column provided to filter lookup: %w", errMissingParameter)
+	}
+
+	options.Mode = strings.ToLower(options.Mode)
+	switch options.Mode {
+	case "":
+		options.Mode = "replace"
+	case "replace", "merge":
+	default:
+		logFilter.Errorf("Unknown mode %s", options.Mode)
+		return nil, fmt.Errorf("unknown lookup mode %s: %w", options.Mode, errWrongParameterValue)
+	}
+
+	options.Miss = strings.ToLower(options.Miss)
+	switch options.Miss {
+	case "":
+		options.Miss = "keep"
+	case "keep", "null", "drop", "fail":
+	default:
+		logFilter.Errorf("Unknown miss behavior %s", options.Miss)
+		return nil, fmt.Errorf("unknown lookup miss behavior %s: %w", options.Miss, errWrongParameterValue)
+	}
+
+	if options.CacheSize <= 0 {
+		options.CacheSize = defaultLookupCacheSize
+	}
+
+	logFilter.Infof("Will apply lookup filter by %s (%s, %s on miss) on:", options.Column, options.Mode, options.Miss)
+
+	for name, value := range mParam {
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	return &LookupFilter{
+		lookup:  lookup,
+		options: options,
+		columns: mParam,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+//find return the record having the key value, from the cache if it has already been looked for.
+func (lf *LookupFilter) find(value string) (types.Record, error) {
+	if e, ok := lf.entries[value]; ok {
+		lf.lru.MoveToFront(e)
+		return e.Value.(*lookupEntry).record, nil
+	}
+
+	record, err := lf.lookup(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// The misses are also cached to avoid looking for them again
+	lf.entries[value] = lf.lru.PushFront(&lookupEntry{value: value, record: record})
+
+	if lf.lru.Len() > lf.options.CacheSize {
+		oldest := lf.lru.Back()
+		lf.lru.Remove(oldest)
+		delete(lf.entries, oldest.Value.(*lookupEntry).value)
+	}
+
+	return record, nil
+}
+
+// Filter : set the columns with the ones of the record of the lookup datasource having the same key.
+func (lf *LookupFilter) Filter(in types.Record) ([]types.Record, error) {
+	var found types.Record
+
+	value, ok := in[lf.options.Column]
+	if ok && value != types.NullValue {
+		var err error
+
+		if found, err = lf.find(value); err != nil {
+			return nil, fmt.Errorf("looking for %s: %w", value, err)
+		}
+	}
+
+	if found == nil {
+		switch lf.options.Miss {
+		case "drop":
+			return nil, nil
+		case "fail":
+			return nil, fmt.Errorf("no record found for %s=%s: %w", lf.options.Column, value, errNotFound)
+		case "keep":
+			return []types.Record{in}, nil
+		}
+	}
+
+	out := make(types.Record, len(in))
+
+	for col, value := range in {
+		out[col] = value
+	}
+
+	for col, lookupCol := range lf.columns {
+		if current, ok := in[col]; ok && current != types.NullValue && lf.options.Mode == "merge" {
+			continue
+		}
+
+		if found == nil {
+			out[col] = types.NullValue
+			continue
+		}
+
+		value, ok := found[lookupCol]
+		if !ok {
+			value = types.NullValue
+		}
+
+		out[col] = value
+	}
+
+	return []types.Record{out}, nil
+}
@@ -0,0 +1,146 @@
+package filter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//newLookup return a lookup function on a mapping table and the counter of its calls.
+func newLookup() (filter.LookupFunc, *int) {
+	calls := 0
+	mapping := map[string]types.Record{
+		"E": {"code": "E", "id": "13", "label": "electric"},
+		"W": {"code": "W", "id": "11", "label": "water"},
+	}
+
+	return func(value string) (types.Record, error) {
+		calls++
+
+		if value == "error" {
+			return nil, fmt.Errorf("fake error")
+		}
+
+		return mapping[value], nil
+	}, &calls
+}
+
+func TestFilterLookupOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		options  filter.LookupOptions
+		in       types.Record
+		expected []types.Record
+	}{
+		{filter.LookupOptions{Column: "type"}, types.Record{"type": "E", "label": "old"}, []types.Record{{"type": "13", "label": "electric"}}},
+		{filter.LookupOptions{Column: "type", Mode: "merge"}, types.Record{"type": "E", "label": "old"}, []types.Record{{"type": "E", "label": "old"}}},
+		{filter.LookupOptions{Column: "type", Mode: "merge"}, types.Record{"type": "E", "label": types.NullValue}, []types.Record{{"type": "E", "label": "electric"}}},
+		{filter.LookupOptions{Column: "type"}, types.Record{"type": "?", "label": "old"}, []types.Record{{"type": "?", "label": "old"}}},
+		{filter.LookupOptions{Column: "type", Miss: "null"}, types.Record{"type": "?", "label": "old"}, []types.Record{{"type": types.NullValue, "label": types.NullValue}}},
+		{filter.LookupOptions{Column: "type", Miss: "drop"}, types.Record{"type": "?", "label": "old"}, []types.Record{}},
+		{filter.LookupOptions{Column: "type", Miss: "drop"}, types.Record{"type": types.NullValue}, []types.Record{}},
+		{filter.LookupOptions{Column: "type", Miss: "drop"}, types.Record{"label": "old"}, []types.Record{}},
+	}
+
+	for _, test := range tests {
+		lookup, _ := newLookup()
+
+		f, err := filter.NewLookupFilter(log, lookup, test.options, map[string]string{"type": "id", "label": "label"})
+		if err != nil {
+			t.Fatalf("NewLookupFilter should not returns an error, returned: %v", err)
+		}
+
+		out, err := f.Filter(test.in)
+		if err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+
+		if len(out) != len(test.expected) {
+			t.Fatalf("The filter with %v on %v should return %v, returned %v", test.options, test.in, test.expected, out)
+		}
+
+		for i, e := range test.expected {
+			for col, value := range e {
+				if out[i][col] != value {
+					t.Errorf("The filter with %v on %v should return %v, returned %v", test.options, test.in, test.expected, out)
+				}
+			}
+		}
+	}
+}
+
+func TestFilterLookupCache(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	lookup, calls := newLookup()
+
+	f, err := filter.NewLookupFilter(log, lookup, filter.LookupOptions{Column: "type", CacheSize: 2}, map[string]string{"label": "label"})
+	if err != nil {
+		t.Fatalf("NewLookupFilter should not returns an error, returned: %v", err)
+	}
+
+	for _, value := range []string{"E", "W", "E", "?", "?", "E", "W"} {
+		if _, err = f.Filter(types.Record{"type": value}); err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+	}
+
+	// E, W, E(cached), ?, ?(cached), E(cached), W (evicted by ?)
+	if *calls != 4 {
+		t.Errorf("The lookup should have been called 4 times, it was called %d times", *calls)
+	}
+}
+
+func TestFilterLookupFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	lookup, _ := newLookup()
+	mParams := map[string]string{"label": "label"}
+
+	_, err := filter.NewLookupFilter(log, lookup, filter.LookupOptions{Column: "type"}, nil)
+	if err == nil {
+		t.Errorf("NewLookupFilter without parameters should returns an error")
+	}
+
+	_, err = filter.NewLookupFilter(log, lookup, filter.LookupOptions{}, mParams)
+	if err == nil {
+		t.Errorf("NewLookupFilter without key column should returns an error")
+	}
+
+	_, err = filter.NewLookupFilter(log, lookup, filter.LookupOptions{Column: "type", Mode: "unknown"}, mParams)
+	if err == nil {
+		t.Errorf("NewLookupFilter with wrong mode should returns an error")
+	}
+
+	_, err = filter.NewLookupFilter(log, lookup, filter.LookupOptions{Column: "type", Miss: "unknown"}, mParams)
+	if err == nil {
+		t.Errorf("NewLookupFilter with wrong miss behavior should returns an error")
+	}
+
+	f, err := filter.NewLookupFilter(log, lookup, filter.LookupOptions{Column: "type", Miss: "fail"}, mParams)
+	if err != nil {
+		t.Fatalf("NewLookupFilter should not returns an error, returned: %v", err)
+	}
+
+	if _, err = f.Filter(types.Record{"type": "?"}); err == nil {
+		t.Errorf("Filter should returns an error on miss")
+	}
+
+	if _, err = f.Filter(types.Record{"type": "error"}); err == nil {
+		t.Errorf("Filter should returns an error if the lookup fails")
+	}
+
+	if _, err = filter.NewFilter(log, "lookup", nil, mParams); err == nil {
+		t.Errorf("NewFilter lookup without datasource should returns an error")
+	}
+}
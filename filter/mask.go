@@ -0,0 +1,239 @@
+package filter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Number of rounds of the Feistel network used to scramble the digits, must be even.
+const maskRounds = 8
+
+// Number of characters kept at the end of the value by the redact method if not provided.
+const defaultRedactKeep = 4
+
+// maskMethod describes how a column is masked.
+type maskMethod struct {
+	name string
+	keep int
+}
+
+// MaskFilter specific type for mask filter operation.
+type MaskFilter struct {
+	secret  []byte
+	columns map[string]maskMethod
+}
+
+func parseMaskMethod(value string) (maskMethod, error) {
+	parts := strings.SplitN(strings.ToLower(strings.TrimSpace(value)), ":", 2)
+	method := maskMethod{name: parts[0]}
+
+	switch method.name {
+	case "name", "firstname", "lastname", "email", "phone", "address", "digits", "hash", "null":
+		if len(parts) != 1 {
+			return method, fmt.Errorf("mask method %s does not take an argument: %w", method.name, errWrongParameterValue)
+		}
+	case "redact":
+		method.keep = defaultRedactKeep
+
+		if len(parts) == 2 {
+			keep, err := strconv.Atoi(parts[1])
+			if err != nil || keep < 0 {
+				return method, fmt.Errorf("mask method redact needs a positive number of kept characters, not %s: %w", parts[1], errWrongParameterValue)
+			}
+
+			method.keep = keep
+		}
+	default:
+		return method, fmt.Errorf("unknown mask method %s: %w", method.name, errWrongParameterValue)
+	}
+
+	return method, nil
+}
+
+func newMaskFilter(log *logrus.Entry, aParam []string, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "mask")
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to mask nothing")
+		return nil, fmt.Errorf("filter mask refuse to mask nothing: %w", errMissingParameter)
+	}
+
+	if len(aParam) == 0 {
+		logFilter.Error("Missing secret in AParameters")
+		return nil, fmt.Errorf("no secret provided to filter mask: %w", errMissingParameter)
+	}
+
+	secret, err := parseField("secret", aParam[0], newTmplEnv())
+	if err != nil {
+		logFilter.Errorf("unable to parse the template of the secret: %v", err)
+		return nil, err
+	}
+
+	if secret == "" {
+		logFilter.Error("Empty secret")
+		return nil, fmt.Errorf("empty secret provided to filter mask: %w", errWrongParameterValue)
+	}
+
+	columns := make(map[string]maskMethod)
+
+	logFilter.Info("Will apply mask filter on:")
+
+	for name, value := range mParam {
+		method, err := parseMaskMethod(value)
+		if err != nil {
+			logFilter.Errorf("unable to parse the mask method for %s (%s): %v", name, value, err)
+			return nil, err
+		}
+
+		columns[name] = method
+
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	return &MaskFilter{secret: []byte(secret), columns: columns}, nil
+}
+
+//sum return the keyed hash of the value, the method is part of the key so each method gives independent results.
+func (mf *MaskFilter) sum(method string, value string) []byte {
+	mac := hmac.New(sha256.New, mf.secret)
+	mac.Write([]byte(method + "\x00" + value))
+
+	return mac.Sum(nil)
+}
+
+//pick choose deterministically an element of the list from 8 bytes of the sum.
+func pick(list []string, sum []byte, offset int) string {
+	return list[binary.BigEndian.Uint64(sum[offset:offset+8])%uint64(len(list))]
+}
+
+//roundValue return the value added to a half of the digits by a round of the Feistel network.
+func (mf *MaskFilter) roundValue(round int, half string) *big.Int {
+	return new(big.Int).SetBytes(mf.sum("digits", fmt.Sprintf("%d\x00%s", round, half)))
+}
+
+//feistel scramble a string of digits by a keyed bijection, two different inputs of the same length give two different outputs.
+func (mf *MaskFilter) feistel(digits string) string {
+	if len(digits) == 1 {
+		d := new(big.Int).SetInt64(int64(digits[0] - '0'))
+		d.Add(d, mf.roundValue(0, "")).Mod(d, big.NewInt(10))
+
+		return d.String()
+	}
+
+	la, lb := len(digits)/2, len(digits)-len(digits)/2
+	a, b := digits[:la], digits[la:]
+
+	for round := 0; round < maskRounds; round++ {
+		c, _ := new(big.Int).SetString(a, 10)
+		mod := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(la)), nil)
+		c.Add(c, mf.roundValue(round, b)).Mod(c, mod)
+
+		s := c.String()
+		a, b = b, strings.Repeat("0", la-len(s))+s
+		la, lb = lb, la
+	}
+
+	return a + b
+}
+
+//scrambleDigits replace the digits of the value keeping all the other characters at their position.
+func (mf *MaskFilter) scrambleDigits(value string) string {
+	positions := make([]int, 0, len(value))
+	digits := make([]byte, 0, len(value))
+
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			positions = append(positions, i)
+			digits = append(digits, value[i])
+		}
+	}
+
+	if len(digits) == 0 {
+		return value
+	}
+
+	scrambled := mf.feistel(string(digits))
+	out := []byte(value)
+
+	for i, pos := range positions {
+		out[pos] = scrambled[i]
+	}
+
+	return string(out)
+}
+
+//redact replace all the characters except the last ones by stars.
+func redact(value string, keep int) string {
+	runes := []rune(value)
+	for i := 0; i < len(runes)-keep; i++ {
+		runes[i] = '*'
+	}
+
+	return string(runes)
+}
+
+//mask return the substitute of the value for the method.
+func (mf *MaskFilter) mask(method maskMethod, value string) string {
+	if method.name == "null" {
+		return types.NullValue
+	}
+
+	if value == types.NullValue || value == "" {
+		return value
+	}
+
+	sum := mf.sum(method.name, value)
+
+	switch method.name {
+	case "name":
+		return pick(maskFirstNames, sum, 0) + " " + pick(maskLastNames, sum, 8)
+	case "firstname":
+		return pick(maskFirstNames, sum, 0)
+	case "lastname":
+		return pick(maskLastNames, sum, 0)
+	case "email":
+		// The 128 bits of the hexadecimal part keep the emails unique, the collisions are negligible even on billions of values
+		local := strings.ToLower(pick(maskFirstNames, sum, 0) + "." + pick(maskLastNames, sum, 8))
+		return fmt.Sprintf("%s.%s@%s", local, hex.EncodeToString(sum[16:32]), pick(maskDomains, sum, 24))
+	case "address":
+		number := binary.BigEndian.Uint64(sum[0:8])%999 + 1
+		return fmt.Sprintf("%d %s %s, %s", number, pick(maskStreetNames, sum, 8), pick(maskStreetTypes, sum, 16), pick(maskCities, sum, 24))
+	case "phone", "digits":
+		return mf.scrambleDigits(value)
+	case "redact":
+		return redact(value, method.keep)
+	case "hash":
+		return hex.EncodeToString(sum)
+	}
+
+	return value
+}
+
+// Filter : replace the content of the columns by their masked values (the columns not present are ignored).
+func (mf *MaskFilter) Filter(in types.Record) ([]types.Record, error) {
+	out := make(types.Record, len(in))
+
+	for col, value := range in {
+		out[col] = value
+	}
+
+	for col, method := range mf.columns {
+		value, ok := in[col]
+		if !ok {
+			continue
+		}
+
+		out[col] = mf.mask(method, value)
+	}
+
+	return []types.Record{out}, nil
+}
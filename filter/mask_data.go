@@ -0,0 +1,34 @@
+package filter
+
+// Values used by the mask filter to build realistic substitutes.
+var (
+	maskFirstNames = []string{
+		"Alice", "Amelia", "Anna", "Arthur", "Benjamin", "Camille", "Charles", "Charlotte", "Chloe", "Daniel",
+		"David", "Elena", "Emma", "Ethan", "Eva", "Felix", "Gabriel", "Grace", "Hannah", "Hugo",
+		"Isabel", "Jack", "James", "Jules", "Julia", "Laura", "Leo", "Liam", "Lucas", "Lucy",
+		"Marie", "Martin", "Mia", "Nathan", "Noah", "Nora", "Olivia", "Oscar", "Paul", "Rose",
+		"Samuel", "Sarah", "Sofia", "Thomas", "Victor", "Victoria", "William", "Yasmine", "Zoe", "Adam",
+	}
+
+	maskLastNames = []string{
+		"Adams", "Baker", "Bernard", "Brown", "Campbell", "Carter", "Clark", "Davis", "Dubois", "Durand",
+		"Evans", "Fischer", "Garcia", "Green", "Hall", "Harris", "Hoffmann", "Jackson", "Johnson", "King",
+		"Lambert", "Laurent", "Lee", "Lewis", "Lopez", "Martin", "Martinez", "Meyer", "Miller", "Moore",
+		"Moreau", "Muller", "Nelson", "Parker", "Petit", "Roberts", "Robinson", "Rossi", "Roux", "Schmidt",
+		"Scott", "Smith", "Taylor", "Thomas", "Thompson", "Walker", "Weber", "White", "Williams", "Wilson",
+	}
+
+	maskStreetNames = []string{
+		"Acacia", "Ash", "Bay", "Birch", "Bridge", "Castle", "Cedar", "Church", "Elm", "Forest",
+		"Garden", "Hill", "Lake", "Maple", "Meadow", "Mill", "Oak", "Park", "Pine", "River",
+		"Rose", "School", "Spring", "Station", "Sunset", "Valley", "Victoria", "Willow", "Windsor", "York",
+	}
+
+	maskStreetTypes = []string{"Street", "Avenue", "Road", "Lane", "Drive", "Boulevard", "Way", "Place"}
+
+	maskCities = []string{
+		"Springfield", "Riverside", "Fairview", "Greenville", "Kingston", "Lakewood", "Madison", "Oakland", "Salem", "Westfield",
+	}
+
+	maskDomains = []string{"example.com", "example.org", "example.net"}
+)
@@ -0,0 +1,178 @@
+package filter_test
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func newMaskFilter(t *testing.T, secret string) filter.Filter {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	mParams := map[string]string{
+		"name":    "name",
+		"email":   "email",
+		"phone":   "phone",
+		"address": "address",
+		"card":    "redact",
+		"iban":    "redact:2",
+		"ssn":     "digits",
+		"notes":   "null",
+	}
+
+	f, err := filter.NewFilter(log, "mask", []string{secret}, mParams)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	return f
+}
+
+func TestFilterMaskOk(t *testing.T) {
+	f := newMaskFilter(t, "s3cr3t")
+
+	in := types.Record{
+		"id":      "1",
+		"name":    "John Doe",
+		"email":   "john.doe@corp.com",
+		"phone":   "+33 6 12 34 56 78",
+		"address": "1 rue de la Paix, Paris",
+		"card":    "4111111111111111",
+		"iban":    "FR76",
+		"ssn":     "123-45-6789",
+		"notes":   "secret notes",
+	}
+
+	out, err := filterOne(t, f, in)
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if out["id"] != "1" {
+		t.Errorf("The id should not be masked, it is '%s'", out["id"])
+	}
+
+	for _, col := range []string{"name", "email", "phone", "address", "ssn"} {
+		if out[col] == in[col] || out[col] == "" {
+			t.Errorf("The column %s should be masked, it is '%s'", col, out[col])
+		}
+	}
+
+	if !regexp.MustCompile(`^[a-z]+\.[a-z]+\.[0-9a-f]{32}@example\.(com|org|net)$`).MatchString(out["email"]) {
+		t.Errorf("The masked email should look like an email, it is '%s'", out["email"])
+	}
+
+	if !regexp.MustCompile(`^\+\d\d \d \d\d \d\d \d\d \d\d$`).MatchString(out["phone"]) {
+		t.Errorf("The masked phone should keep the format, it is '%s'", out["phone"])
+	}
+
+	if !regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`).MatchString(out["ssn"]) {
+		t.Errorf("The masked digits should keep the format, it is '%s'", out["ssn"])
+	}
+
+	if out["card"] != "************1111" {
+		t.Errorf("The card should be redacted, it is '%s'", out["card"])
+	}
+
+	if out["iban"] != "**76" {
+		t.Errorf("The iban should be redacted, it is '%s'", out["iban"])
+	}
+
+	if out["notes"] != types.NullValue {
+		t.Errorf("The notes should be null, it is '%s'", out["notes"])
+	}
+
+	// Same secret, same result
+	again, _ := filterOne(t, newMaskFilter(t, "s3cr3t"), in)
+	for col, value := range out {
+		if again[col] != value {
+			t.Errorf("The masking of %s should be deterministic, '%s' != '%s'", col, value, again[col])
+		}
+	}
+
+	// Other secret, other result
+	other, _ := filterOne(t, newMaskFilter(t, "other"), in)
+	if other["email"] == out["email"] {
+		t.Errorf("The masking should depend on the secret")
+	}
+}
+
+func TestFilterMaskDigitsUnique(t *testing.T) {
+	f := newMaskFilter(t, "s3cr3t")
+	seen := make(map[string]string)
+
+	for _, ssn := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"} {
+		out, _ := filterOne(t, f, types.Record{"ssn": ssn})
+		if previous, ok := seen[out["ssn"]]; ok {
+			t.Errorf("%s and %s are masked to the same value %s", previous, ssn, out["ssn"])
+		}
+
+		seen[out["ssn"]] = ssn
+	}
+
+	for i := 0; i < 1000; i++ {
+		ssn := fmt.Sprintf("%03d", i)
+		out, _ := filterOne(t, f, types.Record{"ssn": ssn})
+
+		if previous, ok := seen[out["ssn"]]; ok {
+			t.Fatalf("%s and %s are masked to the same value %s", previous, ssn, out["ssn"])
+		}
+
+		seen[out["ssn"]] = ssn
+	}
+}
+
+func TestFilterMaskEnvSecretOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	os.Setenv("KAMINO_TEST_MASK_SECRET", "s3cr3t")
+	defer os.Unsetenv("KAMINO_TEST_MASK_SECRET")
+
+	f, err := filter.NewFilter(log, "mask", []string{`{{ index .Environments "KAMINO_TEST_MASK_SECRET" }}`}, map[string]string{"email": "email"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	in := types.Record{"email": "john.doe@corp.com"}
+	out, _ := filterOne(t, f, in)
+	expected, _ := filterOne(t, newMaskFilter(t, "s3cr3t"), in)
+
+	if out["email"] != expected["email"] {
+		t.Errorf("The secret should be read from the environment, '%s' != '%s'", out["email"], expected["email"])
+	}
+}
+
+func TestFilterMaskFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "mask", []string{"s3cr3t"}, nil)
+	if err == nil {
+		t.Errorf("NewFilter mask without parameters should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "mask", nil, map[string]string{"email": "email"})
+	if err == nil {
+		t.Errorf("NewFilter mask without secret should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "mask", []string{"s3cr3t"}, map[string]string{"email": "unknown"})
+	if err == nil {
+		t.Errorf("NewFilter mask with unknown method should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "mask", []string{"s3cr3t"}, map[string]string{"card": "redact:x"})
+	if err == nil {
+		t.Errorf("NewFilter mask with wrong redact argument should returns an error")
+	}
+}
@@ -29,7 +29,7 @@ func newOnlyFilter(log *logrus.Entry, aParam []string) (Filter, error) {
 }
 
 // Filter : Only the content of column by provided values (insert the column if not present).
-func (of *OnlyFilter) Filter(in types.Record) (types.Record, error) {
+func (of *OnlyFilter) Filter(in types.Record) ([]types.Record, error) {
 	out := make(types.Record, len(in))
 
 	for _, col := range of.columns {
@@ -39,5 +39,5 @@ func (of *OnlyFilter) Filter(in types.Record) (types.Record, error) {
 		}
 	}
 
-	return out, nil
+	return []types.Record{out}, nil
 }
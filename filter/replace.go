@@ -2,8 +2,6 @@ package filter
 
 import (
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/provider/types"
@@ -27,15 +25,8 @@ func newReplaceFilter(log *logrus.Entry, mParam map[string]string) (Filter, erro
 		return nil, fmt.Errorf("filter replace refuse to replace nothing: %w", errWrongParameterValue)
 	}
 
-	envVar := make(map[string]string)
 	columns := make(map[string]string)
-
-	for _, v := range os.Environ() {
-		splitV := strings.Split(v, "=")
-		envVar[splitV[0]] = splitV[1]
-	}
-
-	data := tmplEnv{Environments: envVar}
+	data := newTmplEnv()
 
 	logFilter.Info("Will apply replace filter on:")
 
@@ -55,7 +46,7 @@ func newReplaceFilter(log *logrus.Entry, mParam map[string]string) (Filter, erro
 }
 
 // Filter : replace the content of column by provided values (insert the column if not present).
-func (rf *ReplaceFilter) Filter(in types.Record) (types.Record, error) {
+func (rf *ReplaceFilter) Filter(in types.Record) ([]types.Record, error) {
 	out := make(types.Record, len(in))
 
 	for col, value := range in {
@@ -66,5 +57,5 @@ func (rf *ReplaceFilter) Filter(in types.Record) (types.Record, error) {
 		out[col] = value
 	}
 
-	return out, nil
+	return []types.Record{out}, nil
 }
@@ -0,0 +1,161 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/dop251/goja"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Name of the function called for each record if not provided.
+const defaultScriptFunction = "filter"
+
+// Maximum duration of the function call for a record if not provided.
+const defaultScriptTimeout = time.Second
+
+// ScriptFilter specific type for script filter operation.
+type ScriptFilter struct {
+	vm       *goja.Runtime
+	function goja.Callable
+	timeout  time.Duration
+}
+
+func newScriptFilter(log *logrus.Entry, aParam []string, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "script")
+
+	if len(aParam) == 0 {
+		logFilter.Error("Missing script in AParameters")
+		return nil, fmt.Errorf("no script provided to filter script: %w", errMissingParameter)
+	}
+
+	name := defaultScriptFunction
+	if mParam["function"] != "" {
+		name = mParam["function"]
+	}
+
+	timeout := defaultScriptTimeout
+
+	if mParam["timeout"] != "" {
+		var err error
+
+		timeout, err = time.ParseDuration(mParam["timeout"])
+		if err != nil || timeout <= 0 {
+			logFilter.Errorf("Wrong timeout %s", mParam["timeout"])
+			return nil, fmt.Errorf("filter script needs a positive duration as timeout, not %s: %w", mParam["timeout"], errWrongParameterValue)
+		}
+	}
+
+	vm := goja.New()
+	vm.Set("log", func(msg string) { logFilter.Info(msg) })
+
+	// The script is run once, its global variables keep their values between the records
+	_, err := runWithTimeout(vm, timeout, func() (goja.Value, error) {
+		return vm.RunString(strings.Join(aParam, "\n"))
+	})
+	if err != nil {
+		logFilter.Errorf("unable to run the script: %v", err)
+		return nil, fmt.Errorf("running the script: %w", err)
+	}
+
+	function, ok := goja.AssertFunction(vm.Get(name))
+	if !ok {
+		logFilter.Errorf("The script does not define the function %s", name)
+		return nil, fmt.Errorf("the script does not define the function %s: %w", name, errWrongParameterValue)
+	}
+
+	logFilter.Infof("Will apply the function %s of the script (timeout %s)", name, timeout)
+
+	return &ScriptFilter{vm: vm, function: function, timeout: timeout}, nil
+}
+
+//runWithTimeout run the JavaScript code and interrupt it if it lasts more than the timeout.
+func runWithTimeout(vm *goja.Runtime, timeout time.Duration, run func() (goja.Value, error)) (goja.Value, error) {
+	interrupted := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() {
+		vm.Interrupt(fmt.Sprintf("timeout of %s exceeded", timeout))
+		close(interrupted)
+	})
+
+	value, err := run()
+
+	// The timer could have expired after the end of the call, its interruption must not stop the next one
+	if !timer.Stop() {
+		<-interrupted
+		vm.ClearInterrupt()
+	}
+
+	return value, err
+}
+
+//toRecord convert a JavaScript object to a record, the null and undefined properties are NULL.
+func toRecord(obj *goja.Object) types.Record {
+	record := make(types.Record)
+
+	for _, key := range obj.Keys() {
+		value := obj.Get(key)
+		if value == nil || goja.IsNull(value) || goja.IsUndefined(value) {
+			record[key] = types.NullValue
+			continue
+		}
+
+		record[key] = value.String()
+	}
+
+	return record
+}
+
+//toRecords convert the value returned by the function to records: null or undefined for none, an object for one and an array for several.
+func (sf *ScriptFilter) toRecords(value goja.Value) ([]types.Record, error) {
+	if value == nil || goja.IsNull(value) || goja.IsUndefined(value) {
+		return nil, nil
+	}
+
+	obj, ok := value.(*goja.Object)
+	if !ok {
+		return nil, fmt.Errorf("the script returned %s instead of an object: %w", value.String(), errWrongParameterValue)
+	}
+
+	if obj.ClassName() != "Array" {
+		return []types.Record{toRecord(obj)}, nil
+	}
+
+	length := int(obj.Get("length").ToInteger())
+	records := make([]types.Record, 0, length)
+
+	for i := 0; i < length; i++ {
+		element, ok := obj.Get(strconv.Itoa(i)).(*goja.Object)
+		if !ok {
+			return nil, fmt.Errorf("the element %d of the array returned by the script is not an object: %w", i, errWrongParameterValue)
+		}
+
+		records = append(records, toRecord(element))
+	}
+
+	return records, nil
+}
+
+// Filter : call the function of the script with the record and return the records it returned.
+func (sf *ScriptFilter) Filter(in types.Record) ([]types.Record, error) {
+	obj := sf.vm.NewObject()
+
+	for col, value := range in {
+		if value == types.NullValue {
+			obj.Set(col, goja.Null()) //nolint: errcheck
+		} else {
+			obj.Set(col, value) //nolint: errcheck
+		}
+	}
+
+	value, err := runWithTimeout(sf.vm, sf.timeout, func() (goja.Value, error) {
+		return sf.function(goja.Undefined(), obj)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("script: %w", err)
+	}
+
+	return sf.toRecords(value)
+}
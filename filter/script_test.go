@@ -0,0 +1,148 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+const testScript = `
+var count = 0;
+
+function filter(record) {
+	count++;
+	record.rank = count;
+
+	if (record.legendary === "true") {
+		return null;
+	}
+
+	if (record.types !== null && record.types.indexOf(",") >= 0) {
+		return record.types.split(",").map(function(t) {
+			return {name: record.name, types: t, rank: count};
+		});
+	}
+
+	record.comment = undefined;
+	return record;
+}
+
+function loop(record) {
+	for (;;) {}
+}
+
+function wrong(record) {
+	return "wrong";
+}
+
+function throws(record) {
+	throw new Error("failing on " + record.name);
+}
+`
+
+func TestFilterScriptOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	f, err := filter.NewFilter(log, "script", []string{testScript}, nil)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	out, err := f.Filter(types.Record{"name": "Pikachu", "types": "electric", "legendary": "false", "comment": "cute"})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if len(out) != 1 || out[0]["name"] != "Pikachu" || out[0]["rank"] != "1" || out[0]["comment"] != types.NullValue {
+		t.Errorf("The script should return the modified record, returned %v", out)
+	}
+
+	out, err = f.Filter(types.Record{"name": "Mewtwo", "types": "psychic", "legendary": "true"})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if len(out) != 0 {
+		t.Errorf("The script should drop the record, returned %v", out)
+	}
+
+	out, err = f.Filter(types.Record{"name": "Lugia", "types": "psychic,flying", "legendary": "false"})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if len(out) != 2 || out[0]["types"] != "psychic" || out[1]["types"] != "flying" || out[1]["rank"] != "3" {
+		t.Errorf("The script should return two records keeping its state, returned %v", out)
+	}
+
+	out, err = f.Filter(types.Record{"name": "Ditto", "types": types.NullValue, "legendary": "false"})
+	if err != nil {
+		t.Fatalf("Filter should not returns an error, returned: %v", err)
+	}
+
+	if len(out) != 1 || out[0]["types"] != types.NullValue {
+		t.Errorf("The NULL values should be given to the script as null, returned %v", out)
+	}
+}
+
+func TestFilterScriptTimeout(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	f, err := filter.NewFilter(log, "script", []string{testScript}, map[string]string{"function": "loop", "timeout": "50ms"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	if _, err = f.Filter(types.Record{"name": "Pikachu"}); err == nil {
+		t.Errorf("Filter should returns an error on timeout")
+	}
+
+	// The first run of the script is also interrupted
+	_, err = filter.NewFilter(log, "script", []string{"while (true) {}"}, map[string]string{"timeout": "50ms"})
+	if err == nil {
+		t.Errorf("NewFilter should returns an error on timeout of the script")
+	}
+}
+
+func TestFilterScriptFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "script", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter script without parameters should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "script", []string{"function filter(record) {"}, nil)
+	if err == nil {
+		t.Errorf("NewFilter script with syntax error should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "script", []string{testScript}, map[string]string{"function": "unknown"})
+	if err == nil {
+		t.Errorf("NewFilter script without the function should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "script", []string{testScript}, map[string]string{"timeout": "never"})
+	if err == nil {
+		t.Errorf("NewFilter script with wrong timeout should returns an error")
+	}
+
+	for _, function := range []string{"wrong", "throws"} {
+		f, err := filter.NewFilter(log, "script", []string{testScript}, map[string]string{"function": function})
+		if err != nil {
+			t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+		}
+
+		if _, err = f.Filter(types.Record{"name": "Pikachu"}); err == nil {
+			t.Errorf("Filter with the function %s should returns an error", function)
+		}
+	}
+}
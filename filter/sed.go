@@ -51,7 +51,7 @@ func newSedFilter(log *logrus.Entry, mParam map[string]string) (Filter, error) {
 }
 
 // Filter : Sed the content of column by provided values (insert the column if not present).
-func (sf *SedFilter) Filter(in types.Record) (types.Record, error) {
+func (sf *SedFilter) Filter(in types.Record) ([]types.Record, error) {
 	out := make(types.Record, len(in))
 
 	for col, value := range in {
@@ -67,5 +67,5 @@ func (sf *SedFilter) Filter(in types.Record) (types.Record, error) {
 		out[col] = value
 	}
 
-	return out, nil
+	return []types.Record{out}, nil
 }
@@ -0,0 +1,227 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//validateRule is a rule a column must respect.
+type validateRule struct {
+	call
+	// Prepared values of the arguments
+	re     *regexp.Regexp
+	number float64
+	length int
+	values map[string]bool
+	seen   map[string]bool
+}
+
+// ValidateFilter specific type for validate filter operation.
+type ValidateFilter struct {
+	columns []string
+	rules   map[string][]*validateRule
+	onFail  string
+	log     *logrus.Entry
+}
+
+//prepare verify the arguments of the rule and compute the values needed to check it.
+func (rule *validateRule) prepare() error {
+	nbArgs := map[string][2]int{
+		"required":  {0, 0},
+		"notnull":   {0, 0},
+		"unique":    {0, 0},
+		"number":    {0, 0},
+		"regex":     {1, 1},
+		"minlength": {1, 1},
+		"maxlength": {1, 1},
+		"min":       {1, 1},
+		"max":       {1, 1},
+		"date":      {1, 1},
+		"enum":      {1, -1},
+	}
+
+	n, ok := nbArgs[rule.name]
+	if !ok {
+		return fmt.Errorf("unknown validation rule %s: %w", rule.name, errWrongParameterValue)
+	}
+
+	if len(rule.args) < n[0] || (n[1] >= 0 && len(rule.args) > n[1]) {
+		return fmt.Errorf("wrong number of arguments for the validation rule %s: %w", rule.name, errWrongParameterValue)
+	}
+
+	var err error
+
+	switch rule.name {
+	case "unique":
+		rule.seen = make(map[string]bool)
+	case "regex":
+		if rule.re, err = regexp.Compile(rule.args[0]); err != nil {
+			return fmt.Errorf("regular expression %s: %w", rule.args[0], err)
+		}
+	case "minlength", "maxlength":
+		if rule.length, err = strconv.Atoi(rule.args[0]); err != nil || rule.length < 0 {
+			return fmt.Errorf("the length must be a positive number, not %s: %w", rule.args[0], errWrongParameterValue)
+		}
+	case "min", "max":
+		if rule.number, err = strconv.ParseFloat(rule.args[0], 64); err != nil {
+			return fmt.Errorf("the limit must be a number, not %s: %w", rule.args[0], errWrongParameterValue)
+		}
+	case "enum":
+		rule.values = make(map[string]bool)
+		for _, value := range rule.args {
+			rule.values[value] = true
+		}
+	}
+
+	return nil
+}
+
+//check return true if the value respects the rule, the rules other than required and notnull ignore the NULL or missing values.
+func (rule *validateRule) check(value string, present bool) bool {
+	switch rule.name {
+	case "required":
+		return present && value != types.NullValue && strings.TrimSpace(value) != ""
+	case "notnull":
+		return present && value != types.NullValue
+	}
+
+	if !present || value == types.NullValue {
+		return true
+	}
+
+	switch rule.name {
+	case "unique":
+		return !rule.seen[value]
+	case "number":
+		_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return err == nil
+	case "regex":
+		return rule.re.MatchString(value)
+	case "minlength":
+		return utf8.RuneCountInString(value) >= rule.length
+	case "maxlength":
+		return utf8.RuneCountInString(value) <= rule.length
+	case "min", "max":
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return false
+		}
+
+		if rule.name == "min" {
+			return number >= rule.number
+		}
+
+		return number <= rule.number
+	case "enum":
+		return rule.values[value]
+	case "date":
+		_, err := time.Parse(rule.args[0], value)
+		return err == nil
+	}
+
+	return true
+}
+
+func newValidateFilter(log *logrus.Entry, aParam []string, mParam map[string]string) (Filter, error) {
+	logFilter := log.WithField("filter", "validate")
+
+	if len(mParam) == 0 {
+		logFilter.Error("Refuse to validate nothing")
+		return nil, fmt.Errorf("filter validate refuse to validate nothing: %w", errMissingParameter)
+	}
+
+	onFail := "fail"
+	if len(aParam) > 0 {
+		onFail = strings.ToLower(aParam[0])
+	}
+
+	if onFail != "fail" && onFail != "drop" && onFail != "reject" {
+		logFilter.Errorf("Unknown failure behavior %s", onFail)
+		return nil, fmt.Errorf("unknown validation failure behavior %s: %w", onFail, errWrongParameterValue)
+	}
+
+	columns := make([]string, 0, len(mParam))
+	rules := make(map[string][]*validateRule)
+
+	logFilter.Infof("Will apply validate filter (%s on failure) on:", onFail)
+
+	for name, value := range mParam {
+		calls, err := parseCalls(value)
+		if err != nil {
+			logFilter.Errorf("unable to parse the validation rules for %s (%s): %v", name, value, err)
+			return nil, fmt.Errorf("parsing %s provided: %w", name, err)
+		}
+
+		for _, c := range calls {
+			rule := &validateRule{call: c}
+			if err = rule.prepare(); err != nil {
+				logFilter.Errorf("unable to parse the validation rules for %s (%s): %v", name, value, err)
+				return nil, fmt.Errorf("parsing %s provided: %w", name, err)
+			}
+
+			rules[name] = append(rules[name], rule)
+		}
+
+		columns = append(columns, name)
+
+		logFilter.Infof("   - %s : %s", name, value)
+	}
+
+	// The violations are reported in the same order for all the records
+	sort.Strings(columns)
+
+	return &ValidateFilter{columns: columns, rules: rules, onFail: onFail, log: logFilter}, nil
+}
+
+//Rejects return true if the invalid records are written to the rejects datasource.
+func (vf *ValidateFilter) Rejects() bool {
+	return vf.onFail == "reject"
+}
+
+// Filter : check the columns respect their rules and fail, drop or reject the record if they do not.
+func (vf *ValidateFilter) Filter(in types.Record) ([]types.Record, error) {
+	violations := make([]string, 0)
+
+	for _, col := range vf.columns {
+		value, present := in[col]
+
+		for _, rule := range vf.rules[col] {
+			if !rule.check(value, present) {
+				violations = append(violations, fmt.Sprintf("%s: %s violated by '%s'", col, rule, value))
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		// The values are only considered as seen when the record is kept
+		for _, col := range vf.columns {
+			for _, rule := range vf.rules[col] {
+				if value, present := in[col]; rule.name == "unique" && present && value != types.NullValue {
+					rule.seen[value] = true
+				}
+			}
+		}
+
+		return []types.Record{in}, nil
+	}
+
+	reason := strings.Join(violations, "; ")
+
+	switch vf.onFail {
+	case "drop":
+		vf.log.Warnf("Record dropped: %s", reason)
+		return nil, nil
+	case "reject":
+		return nil, &RejectError{Filter: "validate", Reason: reason, Record: in}
+	}
+
+	return nil, fmt.Errorf("invalid record: %s: %w", reason, errWrongParameterValue)
+}
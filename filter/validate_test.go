@@ -0,0 +1,169 @@
+package filter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestFilterValidateRules(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		rules  string
+		record types.Record
+		valid  bool
+	}{
+		{"required", types.Record{"col": "a"}, true},
+		{"required", types.Record{"col": " "}, false},
+		{"required", types.Record{"col": types.NullValue}, false},
+		{"required", types.Record{}, false},
+		{"notNull", types.Record{"col": ""}, true},
+		{"notNull", types.Record{"col": types.NullValue}, false},
+		{`regex("^[A-Z]+$")`, types.Record{"col": "ABC"}, true},
+		{`regex("^[A-Z]+$")`, types.Record{"col": "AbC"}, false},
+		{`regex("^\d{3}-\d{4}$")`, types.Record{"col": "555-1234"}, true},
+		{`regex("^[A-Z]+$")`, types.Record{"col": types.NullValue}, true},
+		{"minLength(3) | maxLength(5)", types.Record{"col": "été"}, true},
+		{"minLength(3) | maxLength(5)", types.Record{"col": "ab"}, false},
+		{"minLength(3) | maxLength(5)", types.Record{"col": "abcdef"}, false},
+		{"min(0) | max(100)", types.Record{"col": "42.5"}, true},
+		{"min(0) | max(100)", types.Record{"col": "-1"}, false},
+		{"min(0) | max(100)", types.Record{"col": "101"}, false},
+		{"min(0)", types.Record{"col": "abc"}, false},
+		{"number", types.Record{"col": " 12.5 "}, true},
+		{"number", types.Record{"col": "12,5"}, false},
+		{`enum("fire", "water")`, types.Record{"col": "water"}, true},
+		{`enum("fire", "water")`, types.Record{"col": "grass"}, false},
+		{`date("2006-01-02")`, types.Record{"col": "1996-02-27"}, true},
+		{`date("2006-01-02")`, types.Record{"col": "27/02/1996"}, false},
+		{`required | enum("fire", "water")`, types.Record{}, false},
+	}
+
+	for _, test := range tests {
+		f, err := filter.NewFilter(log, "validate", []string{"drop"}, map[string]string{"col": test.rules})
+		if err != nil {
+			t.Fatalf("NewFilter should not returns an error for %s, returned: %v", test.rules, err)
+		}
+
+		out, err := f.Filter(test.record)
+		if err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+
+		if valid := len(out) == 1; valid != test.valid {
+			t.Errorf("The validity of %v with %s should be %v", test.record, test.rules, test.valid)
+		}
+	}
+}
+
+func TestFilterValidateUnique(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	f, err := filter.NewFilter(log, "validate", []string{"drop"}, map[string]string{"id": "unique", "name": "required"})
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	tests := []struct {
+		record types.Record
+		valid  bool
+	}{
+		{types.Record{"id": "1", "name": "Alice"}, true},
+		{types.Record{"id": "2", "name": ""}, false},
+		{types.Record{"id": "2", "name": "Bob"}, true},
+		{types.Record{"id": "1", "name": "Charlie"}, false},
+		{types.Record{"id": types.NullValue, "name": "Dave"}, true},
+		{types.Record{"id": types.NullValue, "name": "Eve"}, true},
+	}
+
+	for _, test := range tests {
+		out, err := f.Filter(test.record)
+		if err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+
+		if valid := len(out) == 1; valid != test.valid {
+			t.Errorf("The validity of %v should be %v", test.record, test.valid)
+		}
+	}
+}
+
+func TestFilterValidateOnFailure(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	mParams := map[string]string{"id": "required", "type": `enum("fire", "water")`}
+	in := types.Record{"type": "grass"}
+
+	f, err := filter.NewFilter(log, "validate", nil, mParams)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	if _, err = f.Filter(in); err == nil {
+		t.Errorf("Filter should returns an error by default")
+	}
+
+	if r, ok := f.(filter.Rejecter); !ok || r.Rejects() {
+		t.Errorf("The filter should not reject records by default")
+	}
+
+	f, err = filter.NewFilter(log, "validate", []string{"Reject"}, mParams)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	if r, ok := f.(filter.Rejecter); !ok || !r.Rejects() {
+		t.Errorf("The filter should reject records")
+	}
+
+	_, err = f.Filter(in)
+
+	var reject *filter.RejectError
+	if !errors.As(err, &reject) {
+		t.Fatalf("Filter should returns a RejectError, returned: %v", err)
+	}
+
+	expected := `id: required violated by ''; type: enum("fire", "water") violated by 'grass'`
+	if reject.Filter != "validate" || reject.Reason != expected || reject.Record["type"] != "grass" {
+		t.Errorf("The RejectError should describe the violated rules, it is %v", reject)
+	}
+
+	out, rejects, err := filter.Apply([]filter.Filter{f}, in)
+	if err != nil || len(out) != 0 || len(rejects) != 1 || !strings.Contains(rejects[0].Error(), "grass") {
+		t.Errorf("Apply should return the rejected record separately, returned %v, %v, %v", out, rejects, err)
+	}
+}
+
+func TestFilterValidateFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "validate", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter validate without parameters should returns an error")
+	}
+
+	_, err = filter.NewFilter(log, "validate", []string{"ignore"}, map[string]string{"id": "required"})
+	if err == nil {
+		t.Errorf("NewFilter validate with wrong failure behavior should returns an error")
+	}
+
+	for _, rules := range []string{"", "unknown", "required(1)", "regex", `regex("(")`, `minLength("x")`, "maxLength(-1)", `min("x")`, "enum", "required |"} {
+		_, err = filter.NewFilter(log, "validate", nil, map[string]string{"id": rules})
+		if err == nil {
+			t.Errorf("NewFilter validate with wrong rules '%s' should returns an error", rules)
+		}
+	}
+}
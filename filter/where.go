@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// WhereFilter specific type for where filter operation.
+type WhereFilter struct {
+	predicates []expression
+}
+
+func newWhereFilter(log *logrus.Entry, aParam []string) (Filter, error) {
+	logFilter := log.WithField("filter", "where")
+
+	if len(aParam) == 0 {
+		logFilter.Error("Missing predicate in AParameters")
+		return nil, fmt.Errorf("no predicate provided to filter where: %w", errMissingParameter)
+	}
+
+	predicates := make([]expression, 0, len(aParam))
+
+	logFilter.Info("Will keep the records matching:")
+
+	for _, value := range aParam {
+		predicate, err := parseExpression(value)
+		if err != nil {
+			logFilter.Errorf("unable to parse the predicate %s: %v", value, err)
+			return nil, fmt.Errorf("parsing predicate %s: %w", value, err)
+		}
+
+		predicates = append(predicates, predicate)
+
+		logFilter.Infof("   - %s", value)
+	}
+
+	return &WhereFilter{predicates: predicates}, nil
+}
+
+// Filter : keep the record only if it matches all the predicates.
+func (wf *WhereFilter) Filter(in types.Record) ([]types.Record, error) {
+	for _, predicate := range wf.predicates {
+		if !truth(predicate.eval(in)) {
+			return nil, nil
+		}
+	}
+
+	return []types.Record{in}, nil
+}
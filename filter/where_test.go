@@ -0,0 +1,93 @@
+package filter_test
+
+import (
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestFilterWhereOk(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	tests := []struct {
+		predicate string
+		record    types.Record
+		kept      bool
+	}{
+		{`legendary == "true" && generation < 3`, types.Record{"legendary": "true", "generation": "1"}, true},
+		{`legendary == "true" && generation < 3`, types.Record{"legendary": "true", "generation": "10"}, false},
+		{`legendary == "true" && generation < 3`, types.Record{"legendary": "false", "generation": "1"}, false},
+		{`legendary || generation >= 3`, types.Record{"legendary": "false", "generation": "3"}, true},
+		{`!legendary`, types.Record{"legendary": "0"}, true},
+		{`legendary == true`, types.Record{"legendary": "1"}, true},
+		{`(hp > 50 || hp < 10) && name != 'Pikachu'`, types.Record{"hp": "5", "name": "Raichu"}, true},
+		{`(hp > 50 || hp < 10) && name != 'Pikachu'`, types.Record{"hp": "5", "name": "Pikachu"}, false},
+		{`name < "b"`, types.Record{"name": "abra"}, true},
+		{`name =~ "^Pika" && email !~ "@example\\.com$"`, types.Record{"name": "Pikachu", "email": "pika@corp.com"}, true},
+		{`id =~ "^\d+$" && name =~ '^\w+ \'s$'`, types.Record{"id": "25", "name": "Ash 's"}, true},
+		{`id =~ "^\d+$"`, types.Record{"id": "d+"}, false},
+		{"`first name` == \"Ash\"", types.Record{"first name": "Ash"}, true},
+		{`comment == null`, types.Record{"comment": types.NullValue}, true},
+		{`comment == null`, types.Record{}, true},
+		{`comment != null`, types.Record{"comment": ""}, true},
+		{`comment > 1`, types.Record{"comment": types.NullValue}, false},
+		{`weight >= -1.5`, types.Record{"weight": "-1.5"}, true},
+	}
+
+	for _, test := range tests {
+		f, err := filter.NewFilter(log, "where", []string{test.predicate}, nil)
+		if err != nil {
+			t.Fatalf("NewFilter should not returns an error for %s, returned: %v", test.predicate, err)
+		}
+
+		out, err := f.Filter(test.record)
+		if err != nil {
+			t.Fatalf("Filter should not returns an error, returned: %v", err)
+		}
+
+		if kept := len(out) == 1; kept != test.kept {
+			t.Errorf("The record %v should be kept by %s: %v, filter returned %v", test.record, test.predicate, test.kept, out)
+		}
+	}
+}
+
+func TestFilterWhereSeveralPredicates(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	f, err := filter.NewFilter(log, "where", []string{"generation < 3", "legendary"}, nil)
+	if err != nil {
+		t.Fatalf("NewFilter should not returns an error, returned: %v", err)
+	}
+
+	if out, _ := f.Filter(types.Record{"generation": "1", "legendary": "true"}); len(out) != 1 {
+		t.Errorf("The record matching all the predicates should be kept")
+	}
+
+	if out, _ := f.Filter(types.Record{"generation": "1", "legendary": "false"}); len(out) != 0 {
+		t.Errorf("The record not matching all the predicates should be dropped")
+	}
+}
+
+func TestFilterWhereFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err := filter.NewFilter(log, "where", nil, nil)
+	if err == nil {
+		t.Errorf("NewFilter where without parameters should returns an error")
+	}
+
+	for _, predicate := range []string{"", "a ==", "(a == 1", "a == 1)", `a == "b`, "a # 1", "a =~ b", `a =~ "("`, "a == 1 b"} {
+		_, err = filter.NewFilter(log, "where", []string{predicate}, nil)
+		if err == nil {
+			t.Errorf("NewFilter where with wrong predicate '%s' should returns an error", predicate)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/throttle"
 )
 
 // MockDatasource is fake datasource object for test purpose.
@@ -47,6 +48,8 @@ type MockDatasource struct {
 	TableEmpty    bool
 	MockedDb      *sql.DB
 	WriteBuf      bytes.Buffer
+	Limiters      []*throttle.Limiter
+	Generator     datasource.GeneratorConfig
 }
 
 //GetEngine return the engine enum value.
@@ -64,6 +67,11 @@ func (ds *MockDatasource) IsTransaction() bool {
 	return ds.Transaction
 }
 
+//GetLimiters return the throughput limiters of the datasource and of its host.
+func (ds *MockDatasource) GetLimiters() []*throttle.Limiter {
+	return ds.Limiters
+}
+
 //GetNamedTag return the value of the tag with the provided name or "" if not exists.
 func (ds *MockDatasource) GetNamedTag(name string) string {
 	for _, tag := range ds.Tags {
@@ -80,6 +88,11 @@ func (ds *MockDatasource) GetName() string {
 	return ds.Name
 }
 
+//GetGenerator return the description of the tables of a generator datasource.
+func (ds *MockDatasource) GetGenerator() datasource.GeneratorConfig {
+	return ds.Generator
+}
+
 //GetHash returns uniq hash for the datasource final destination (more than one datasource could have the same hash by example same database engine).
 func (ds *MockDatasource) GetHash(log *logrus.Entry, admin bool, nodb bool) string {
 	var toHash string
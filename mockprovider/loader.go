@@ -10,11 +10,13 @@ import (
 
 //MockLoader specifc state for database Saver provider.
 type MockLoader struct {
-	MockName   string
-	Content    []map[string]string
-	CurrentRow int
-	ErrorClose error
-	ErrorLoad  error
+	MockName     string
+	Content      []map[string]string
+	CurrentRow   int
+	ErrorClose   error
+	ErrorLoad    error
+	MockColumns  []types.Column
+	ErrorColumns error
 }
 
 //Next moves to next record and return false if there is no more records.
@@ -47,3 +49,8 @@ func (ml *MockLoader) Close(log *logrus.Entry) error {
 func (ml *MockLoader) Name() string {
 	return ml.MockName
 }
+
+//Columns returns the description of the columns.
+func (ml *MockLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	return ml.MockColumns, ml.ErrorColumns
+}
@@ -0,0 +1,39 @@
+package mockprovider
+
+import (
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//MockLookup specifc state for database Lookup provider.
+type MockLookup struct {
+	Key        string
+	Content    []map[string]string
+	Fetched    []string
+	Closed     int
+	ErrorFetch error
+}
+
+//Fetch return the first record having the key value.
+func (ml *MockLookup) Fetch(log *logrus.Entry, value string) (types.Record, error) {
+	ml.Fetched = append(ml.Fetched, value)
+
+	if ml.ErrorFetch != nil {
+		return nil, ml.ErrorFetch
+	}
+
+	for _, record := range ml.Content {
+		if record[ml.Key] == value {
+			return record, nil
+		}
+	}
+
+	return nil, nil
+}
+
+//Close closes the lookup.
+func (ml *MockLookup) Close(log *logrus.Entry) error {
+	ml.Closed++
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/mockdatasource"
 	"github.com/marema31/kamino/mockprovider"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestOk(t *testing.T) {
@@ -15,13 +16,13 @@ func TestOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "")
+	saver, err := pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
 	mockedSaver := pf.Savers[0]
 
-	loader, err := pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "")
+	loader, err := pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -96,21 +97,21 @@ func TestError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 
 	pf.ErrorLoader = fmt.Errorf("Fake error")
-	saver, err := pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "")
+	saver, err := pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
 
 	pf.ErrorSaver = fmt.Errorf("Fake error")
 	pf.SaverToFail = 1
-	_, err = pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "")
+	_, err = pf.NewSaver(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", "", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
 	mockedSaver := pf.Savers[0]
 
 	pf.ErrorLoader = nil
-	loader, err := pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "")
+	loader, err := pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -118,7 +119,7 @@ func TestError(t *testing.T) {
 
 	pf.LoaderToFail = 1
 	pf.ErrorLoader = fmt.Errorf("Fake error")
-	_, err = pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "")
+	_, err = pf.NewLoader(context.Background(), log, &mockdatasource.MockDatasource{}, "", "", types.LoaderOptions{})
 	if err == nil {
 		t.Fatalf("NewLoader should return error")
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
 )
 
 //MockProvider implement the Provider interface with mocked actions.
@@ -18,10 +19,19 @@ type MockProvider struct {
 	CurrentSaver  int
 	Loader        *MockLoader
 	Savers        []*MockSaver
+	Truncated     []string
+	Contents      map[string][]map[string]string
+	Wheres        []string
+	MockFKs       []types.ForeignKey
+	ErrorFKs      error
+	MockCount     int64
+	ErrorCount    error
+	Lookups       []*MockLookup
+	ErrorLookup   error
 }
 
 //NewLoader analyze the datasource and return mock object implementing Loader.
-func (p *MockProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (provider.Loader, error) {
+func (p *MockProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string, options types.LoaderOptions) (provider.Loader, error) {
 	if p.ErrorLoader != nil && p.CurrentLoader == p.LoaderToFail {
 		err := p.ErrorLoader
 		p.CurrentLoader++
@@ -29,7 +39,13 @@ func (p *MockProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds data
 		return nil, err
 	}
 
-	k := &MockLoader{}
+	content, ok := p.Contents[ds.GetName()+"("+table+")"]
+	if !ok {
+		content = p.Contents[ds.GetName()]
+	}
+
+	k := &MockLoader{Content: content}
+	p.Wheres = append(p.Wheres, where)
 	p.Loader = k
 	p.CurrentLoader++
 
@@ -37,15 +53,42 @@ func (p *MockProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds data
 }
 
 //NewSaver analyze the datasource and return mock object implementing Saver.
-func (p *MockProvider) NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string) (provider.Saver, error) {
+func (p *MockProvider) NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string, options types.SaverOptions) (provider.Saver, error) {
 	if p.ErrorSaver != nil && p.CurrentSaver == p.SaverToFail {
 		p.CurrentSaver++
 		return nil, p.ErrorSaver
 	}
 
-	k := MockSaver{}
+	k := MockSaver{Table: table, Key: key, Mode: mode, Options: options, truncated: &p.Truncated}
 	p.Savers = append(p.Savers, &k)
 	p.CurrentSaver++
 
 	return &k, nil
 }
+
+//ForeignKeys returns the mocked foreign keys.
+func (p *MockProvider) ForeignKeys(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer) ([]types.ForeignKey, error) {
+	return p.MockFKs, p.ErrorFKs
+}
+
+//Count returns the mocked number of records.
+func (p *MockProvider) Count(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (int64, error) {
+	return p.MockCount, p.ErrorCount
+}
+
+//NewLookup return mock object implementing Lookup on the content of the datasource.
+func (p *MockProvider) NewLookup(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string) (provider.Lookup, error) {
+	if p.ErrorLookup != nil {
+		return nil, p.ErrorLookup
+	}
+
+	content, ok := p.Contents[ds.GetName()+"("+table+")"]
+	if !ok {
+		content = p.Contents[ds.GetName()]
+	}
+
+	l := &MockLookup{Key: key, Content: content}
+	p.Lookups = append(p.Lookups, l)
+
+	return l, nil
+}
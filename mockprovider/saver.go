@@ -12,6 +12,12 @@ type MockSaver struct {
 	ErrorClose error
 	ErrorReset error
 	ErrorSave  error
+	Options    types.SaverOptions
+	Table      string
+	Key        string
+	Mode       string
+	Closed     int
+	truncated  *[]string
 }
 
 //Save writes the record to the destination.
@@ -27,6 +33,8 @@ func (ms *MockSaver) Save(log *logrus.Entry, record types.Record) error {
 
 //Close closes the destination.
 func (ms *MockSaver) Close(log *logrus.Entry) error {
+	ms.Closed++
+
 	return ms.ErrorClose
 }
 
@@ -39,3 +47,12 @@ func (ms *MockSaver) Name() string {
 func (ms *MockSaver) Reset(log *logrus.Entry) error {
 	return ms.ErrorReset
 }
+
+//Truncate register the truncation of the destination in the provider.
+func (ms *MockSaver) Truncate(log *logrus.Entry) error {
+	if ms.truncated != nil {
+		*ms.truncated = append(*ms.truncated, ms.Table)
+	}
+
+	return nil
+}
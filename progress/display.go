@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultRefresh = 500 * time.Millisecond
+
+// Display shows the progression of all the running trackers on the last lines of a terminal.
+// It is also the writer of the logs to keep them above the progression lines.
+type Display struct {
+	mu       sync.Mutex
+	out      io.Writer
+	trackers []*Tracker
+	lines    int // Number of progression lines currently displayed
+	refresh  time.Duration
+	stop     chan struct{}
+}
+
+var (
+	currentMu sync.Mutex
+	current   *Display
+)
+
+//IsTerminal return true if the file is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+//NewDisplay returns a display writing on out.
+func NewDisplay(out io.Writer) *Display {
+	return &Display{out: out, refresh: defaultRefresh}
+}
+
+//SetDisplay defines the display used by Register and Unregister, nil to disable the live display.
+func SetDisplay(d *Display) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	current = d
+}
+
+//Active return true if the progression is displayed live.
+func Active() bool {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	return current != nil
+}
+
+//Register adds the tracker to the live display if there is one.
+func Register(t *Tracker) {
+	currentMu.Lock()
+	d := current
+	currentMu.Unlock()
+
+	if d != nil {
+		d.Register(t)
+	}
+}
+
+//Unregister removes the tracker from the live display if there is one.
+func Unregister(t *Tracker) {
+	currentMu.Lock()
+	d := current
+	currentMu.Unlock()
+
+	if d != nil {
+		d.Unregister(t)
+	}
+}
+
+//clear removes the progression lines, the lock must be held.
+func (d *Display) clear() {
+	for ; d.lines > 0; d.lines-- {
+		// Move to the beginning of previous line and erase it
+		fmt.Fprint(d.out, "\x1b[1A\x1b[2K\r")
+	}
+}
+
+//draw writes the progression lines, the lock must be held.
+func (d *Display) draw() {
+	for _, t := range d.trackers {
+		fmt.Fprintln(d.out, t.String())
+	}
+
+	d.lines = len(d.trackers)
+}
+
+//Write writes the log message above the progression lines.
+func (d *Display) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clear()
+	n, err := d.out.Write(p)
+	d.draw()
+
+	return n, err
+}
+
+//Register adds the tracker to the display and starts the refresh if it is the first one.
+func (d *Display) Register(t *Tracker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.trackers = append(d.trackers, t)
+
+	if d.stop == nil {
+		d.stop = make(chan struct{})
+		go d.run(d.stop)
+	}
+}
+
+//Unregister removes the tracker from the display and stops the refresh if it was the last one.
+func (d *Display) Unregister(t *Tracker) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, tracker := range d.trackers {
+		if tracker == t {
+			d.trackers = append(d.trackers[:i], d.trackers[i+1:]...)
+			break
+		}
+	}
+
+	d.clear()
+	d.draw()
+
+	if len(d.trackers) == 0 && d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+}
+
+func (d *Display) run(stop chan struct{}) {
+	ticker := time.NewTicker(d.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			d.clear()
+			d.draw()
+			d.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackerRows(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := newTracker("sync", 100, Rows, 10*time.Second, func() time.Time { return now })
+
+	for i := 0; i < 24; i++ {
+		if tr.Add(10) {
+			t.Errorf("No event should be due before the interval")
+		}
+	}
+
+	now = now.Add(12 * time.Second)
+
+	if !tr.Add(10) {
+		t.Errorf("An event should be due after the interval")
+	}
+
+	if s := tr.String(); s != "sync: 25/100 rows (25.0%), 2 rows/s, ETA 36s" {
+		t.Errorf("The progression is wrong: %s", s)
+	}
+}
+
+func TestTrackerBytesUnknown(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := newTracker("file", 1000, Bytes, time.Second, func() time.Time { return now })
+	tr.Add(250)
+
+	now = now.Add(time.Second)
+
+	if s := tr.String(); s != "file: 1 rows (25.0% of 1000 bytes), 1 rows/s, ETA 3s" {
+		t.Errorf("The progression is wrong: %s", s)
+	}
+
+	tr = newTracker("unknown", 0, Rows, time.Second, func() time.Time { return now })
+	tr.Add(250)
+
+	if s := tr.String(); s != "unknown: 1 rows, 0 rows/s" {
+		t.Errorf("The progression is wrong: %s", s)
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	var out bytes.Buffer
+
+	d := NewDisplay(&out)
+	tr := NewTracker("sync", 10, Rows, time.Second)
+
+	d.Register(tr)
+
+	if _, err := d.Write([]byte("log line\n")); err != nil {
+		t.Fatalf("Write should not return error, returned %v", err)
+	}
+
+	d.Unregister(tr)
+
+	if !strings.HasPrefix(out.String(), "log line\nsync: 0/10 rows") {
+		t.Errorf("The log should be written before the progression lines: %q", out.String())
+	}
+
+	if !strings.HasSuffix(out.String(), "\x1b[1A\x1b[2K\r") {
+		t.Errorf("The progression lines should be erased when no more tracker: %q", out.String())
+	}
+}
+
+func TestActive(t *testing.T) {
+	if Active() {
+		t.Errorf("The live display should not be active by default")
+	}
+
+	SetDisplay(NewDisplay(&bytes.Buffer{}))
+	defer SetDisplay(nil)
+
+	if !Active() {
+		t.Errorf("The live display should be active after SetDisplay")
+	}
+}
@@ -0,0 +1,102 @@
+//Package progress follows and displays the progression of the synchronizations
+package progress
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Units of the total of a tracker.
+const (
+	Rows  = "rows"
+	Bytes = "bytes"
+)
+
+// Tracker follows the progression of one synchronization.
+type Tracker struct {
+	mu        sync.Mutex
+	name      string
+	total     int64  // 0 if unknown
+	unit      string // Rows or Bytes, unit of total
+	rows      int64
+	bytes     int64
+	start     time.Time
+	lastEvent time.Time
+	interval  time.Duration
+	now       func() time.Time
+}
+
+//NewTracker returns a tracker for a synchronization of total rows or bytes (0 if unknown), an event is due every interval.
+func NewTracker(name string, total int64, unit string, interval time.Duration) *Tracker {
+	return newTracker(name, total, unit, interval, time.Now)
+}
+
+func newTracker(name string, total int64, unit string, interval time.Duration, now func() time.Time) *Tracker {
+	start := now()
+
+	return &Tracker{name: name, total: total, unit: unit, start: start, lastEvent: start, interval: interval, now: now}
+}
+
+//Add accounts a record of the provided size and return true if a progress event is due.
+func (t *Tracker) Add(bytes int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rows++
+	t.bytes += int64(bytes)
+
+	now := t.now()
+	if now.Sub(t.lastEvent) < t.interval {
+		return false
+	}
+
+	t.lastEvent = now
+
+	return true
+}
+
+//done return the progression in the unit of the total.
+func (t *Tracker) done() int64 {
+	if t.unit == Bytes {
+		return t.bytes
+	}
+
+	return t.rows
+}
+
+//String returns the description of the progression with the percentage, rate and estimated time remaining if the total is known.
+func (t *Tracker) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elapsed := t.now().Sub(t.start)
+	rate := 0.0
+
+	if elapsed > 0 {
+		rate = float64(t.rows) / elapsed.Seconds()
+	}
+
+	if t.total <= 0 {
+		return fmt.Sprintf("%s: %d rows, %.0f rows/s", t.name, t.rows, rate)
+	}
+
+	done := t.done()
+	if done > t.total {
+		done = t.total
+	}
+
+	percent := float64(done) * 100 / float64(t.total)
+	eta := "unknown"
+
+	if done > 0 && elapsed > 0 {
+		remaining := time.Duration(float64(elapsed) * float64(t.total-done) / float64(done))
+		eta = remaining.Round(time.Second).String()
+	}
+
+	if t.unit == Bytes {
+		return fmt.Sprintf("%s: %d rows (%.1f%% of %d bytes), %.0f rows/s, ETA %s", t.name, t.rows, percent, t.total, rate, eta)
+	}
+
+	return fmt.Sprintf("%s: %d/%d rows (%.1f%%), %.0f rows/s, ETA %s", t.name, t.rows, t.total, percent, rate, eta)
+}
@@ -13,3 +13,6 @@ var ErrMissingParameter = errors.New("MISSING PARAMETER")
 
 //ErrWrongParameterValue raise when a parameter as a wrong value in provider definition.
 var ErrWrongParameterValue = errors.New("WRONG PARAMETER VALUE")
+
+//ErrSaverInit raise when the destination can not be prepared, no record can be saved in it.
+var ErrSaverInit = errors.New("DESTINATION PREPARATION FAILED")
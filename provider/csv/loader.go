@@ -110,3 +110,14 @@ func (cl *KaminoCsvLoader) Close(log *logrus.Entry) error {
 func (cl *KaminoCsvLoader) Name() string {
 	return cl.name
 }
+
+//Columns returns the description of the columns from the CSV header, their types are unknown.
+func (cl *KaminoCsvLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	columns := make([]types.Column, 0, len(cl.colNames))
+
+	for _, col := range cl.colNames {
+		columns = append(columns, types.Column{Name: col, Nullable: true})
+	}
+
+	return columns, nil
+}
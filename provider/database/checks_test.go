@@ -0,0 +1,207 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestDisableForeignKeysMysqlOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	dmock.ExpectExec("SET FOREIGN_KEY_CHECKS=0").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`id`\\) VALUES \\( \\?,\\? \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("SET FOREIGN_KEY_CHECKS=1").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectCommit()
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{DisableForeignKeys: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestDisableForeignKeysMysqlReset(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	dmock.ExpectExec("SET FOREIGN_KEY_CHECKS=0").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`id`\\) VALUES \\( \\?,\\? \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnError(fmt.Errorf("fake error"))
+	dmock.ExpectExec("SET FOREIGN_KEY_CHECKS=1").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectRollback()
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{DisableForeignKeys: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err == nil {
+		t.Fatalf("Save should return error")
+	}
+
+	err = saver.Reset(log)
+	if err != nil {
+		t.Errorf("Saver reset should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestDisableChecksPostgresOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	dmock.ExpectExec("SET LOCAL session_replication_role = replica").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"id\"\\) VALUES \\( \\$1,\\$2 \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectCommit()
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{DisableForeignKeys: true, DisableTriggers: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestDisableForeignKeysPostgresOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	// The NOT DEFERRABLE foreign keys are only disabled by the replica role
+	dmock.ExpectExec("SET LOCAL session_replication_role = replica").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"id\"\\) VALUES \\( \\$1,\\$2 \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectCommit()
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{DisableForeignKeys: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestDisableTriggersMysqlError(t *testing.T) {
+	ddb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{DisableTriggers: true})
+	if err == nil {
+		t.Errorf("NewSaver should return error")
+	}
+}
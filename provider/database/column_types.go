@@ -0,0 +1,207 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Engine independent column types, used to create a table on an engine from the description of a table of another engine.
+const (
+	typeBoolean   = "boolean"
+	typeSmallint  = "smallint"
+	typeInteger   = "integer"
+	typeBigint    = "bigint"
+	typeDecimal   = "decimal"
+	typeFloat     = "float"
+	typeDouble    = "double"
+	typeChar      = "char"
+	typeVarchar   = "varchar"
+	typeText      = "text"
+	typeBlob      = "blob"
+	typeDate      = "date"
+	typeTime      = "time"
+	typeDatetime  = "datetime"
+	typeTimestamp = "timestamp"
+	typeJSON      = "json"
+	typeUUID      = "uuid"
+)
+
+// defaultVarcharLength used when the length of a (var)char column is unknown or when a text column must be indexed.
+const defaultVarcharLength = 255
+
+// Database type names as returned by the drivers (sql.ColumnType.DatabaseTypeName) to engine independent types.
+var mysqlToGeneric = map[string]string{
+	"BIT":        typeBoolean,
+	"TINYINT":    typeSmallint,
+	"SMALLINT":   typeSmallint,
+	"YEAR":       typeSmallint,
+	"MEDIUMINT":  typeInteger,
+	"INT":        typeInteger,
+	"BIGINT":     typeBigint,
+	"DECIMAL":    typeDecimal,
+	"FLOAT":      typeFloat,
+	"DOUBLE":     typeDouble,
+	"CHAR":       typeChar,
+	"VARCHAR":    typeVarchar,
+	"ENUM":       typeVarchar,
+	"SET":        typeVarchar,
+	"TINYTEXT":   typeText,
+	"TEXT":       typeText,
+	"MEDIUMTEXT": typeText,
+	"LONGTEXT":   typeText,
+	"BINARY":     typeBlob,
+	"VARBINARY":  typeBlob,
+	"TINYBLOB":   typeBlob,
+	"BLOB":       typeBlob,
+	"MEDIUMBLOB": typeBlob,
+	"LONGBLOB":   typeBlob,
+	"GEOMETRY":   typeBlob,
+	"DATE":       typeDate,
+	"TIME":       typeTime,
+	"DATETIME":   typeDatetime,
+	"TIMESTAMP":  typeTimestamp,
+	"JSON":       typeJSON,
+}
+
+var postgresToGeneric = map[string]string{
+	"BOOL":        typeBoolean,
+	"INT2":        typeSmallint,
+	"INT4":        typeInteger,
+	"OID":         typeInteger,
+	"INT8":        typeBigint,
+	"NUMERIC":     typeDecimal,
+	"MONEY":       typeDecimal,
+	"FLOAT4":      typeFloat,
+	"FLOAT8":      typeDouble,
+	"BPCHAR":      typeChar,
+	"CHAR":        typeChar,
+	"VARCHAR":     typeVarchar,
+	"NAME":        typeVarchar,
+	"TEXT":        typeText,
+	"BYTEA":       typeBlob,
+	"DATE":        typeDate,
+	"TIME":        typeTime,
+	"TIMETZ":      typeTime,
+	"TIMESTAMP":   typeDatetime,
+	"TIMESTAMPTZ": typeTimestamp,
+	"JSON":        typeJSON,
+	"JSONB":       typeJSON,
+	"UUID":        typeUUID,
+}
+
+// Engine independent types to database column definition.
+var genericToMysql = map[string]string{
+	typeBoolean:   "BOOLEAN",
+	typeSmallint:  "SMALLINT",
+	typeInteger:   "INT",
+	typeBigint:    "BIGINT",
+	typeDecimal:   "DECIMAL",
+	typeFloat:     "FLOAT",
+	typeDouble:    "DOUBLE",
+	typeChar:      "CHAR",
+	typeVarchar:   "VARCHAR",
+	typeText:      "LONGTEXT",
+	typeBlob:      "LONGBLOB",
+	typeDate:      "DATE",
+	typeTime:      "TIME",
+	typeDatetime:  "DATETIME",
+	typeTimestamp: "DATETIME",
+	typeJSON:      "JSON",
+	typeUUID:      "CHAR(36)",
+}
+
+var genericToPostgres = map[string]string{
+	typeBoolean:   "BOOLEAN",
+	typeSmallint:  "SMALLINT",
+	typeInteger:   "INTEGER",
+	typeBigint:    "BIGINT",
+	typeDecimal:   "NUMERIC",
+	typeFloat:     "REAL",
+	typeDouble:    "DOUBLE PRECISION",
+	typeChar:      "CHAR",
+	typeVarchar:   "VARCHAR",
+	typeText:      "TEXT",
+	typeBlob:      "BYTEA",
+	typeDate:      "DATE",
+	typeTime:      "TIME",
+	typeDatetime:  "TIMESTAMP",
+	typeTimestamp: "TIMESTAMP WITH TIME ZONE",
+	typeJSON:      "JSONB",
+	typeUUID:      "UUID",
+}
+
+//genericType return the engine independent type corresponding to the database type name returned by the driver, text if unknown.
+func genericType(engine datasource.Engine, dbType string) string {
+	var (
+		generic string
+		ok      bool
+	)
+
+	switch engine {
+	case datasource.Mysql:
+		generic, ok = mysqlToGeneric[strings.TrimPrefix(strings.ToUpper(dbType), "UNSIGNED ")]
+	case datasource.Postgres:
+		generic, ok = postgresToGeneric[strings.ToUpper(dbType)]
+	}
+
+	if !ok {
+		return typeText
+	}
+
+	return generic
+}
+
+//columnDefinition return the type part of the column definition in a CREATE TABLE statement for the engine.
+func columnDefinition(engine datasource.Engine, col types.Column) (string, error) {
+	generic := col.Type
+	if generic == "" {
+		generic = typeText
+	}
+
+	// Most engines refuse to index unbounded columns, a primary key must have a length
+	if col.PrimaryKey && (generic == typeText || generic == typeBlob) {
+		generic = typeVarchar
+	}
+
+	var (
+		definition string
+		ok         bool
+	)
+
+	switch engine {
+	case datasource.Mysql:
+		if definition, ok = genericToMysql[generic]; !ok {
+			definition = genericToMysql[typeText]
+		}
+	case datasource.Postgres:
+		if definition, ok = genericToPostgres[generic]; !ok {
+			definition = genericToPostgres[typeText]
+		}
+	default:
+		return "", fmt.Errorf("can not create a table on %s engine: %w", datasource.EngineToString(engine), common.ErrWrongParameterValue)
+	}
+
+	switch generic {
+	case typeChar, typeVarchar:
+		length := col.Length
+		if length <= 0 || length > 65535 {
+			length = defaultVarcharLength
+		}
+
+		definition = fmt.Sprintf("%s(%d)", definition, length)
+	case typeDecimal:
+		if col.Precision > 0 {
+			definition = fmt.Sprintf("%s(%d,%d)", definition, col.Precision, col.Scale)
+		}
+	}
+
+	if !col.Nullable || col.PrimaryKey {
+		definition += " NOT NULL"
+	}
+
+	return definition, nil
+}
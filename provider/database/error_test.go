@@ -2,6 +2,7 @@ package database_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -9,7 +10,9 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/common"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestNoTableError(t *testing.T) {
@@ -29,12 +32,12 @@ func TestNoTableError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "", "id", "replace")
+	_, err = database.NewSaver(context.Background(), log, &dest, "", "id", "replace", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
 
-	_, err = database.NewLoader(context.Background(), log, &source, "", "")
+	_, err = database.NewLoader(context.Background(), log, &source, "", "", types.LoaderOptions{})
 	if err == nil {
 		t.Fatalf("NewLoader should return error")
 	}
@@ -52,11 +55,11 @@ func TestNoKeyError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "", "exactCopy")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "", "exactCopy", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "mykey", "replace")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "mykey", "replace", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
@@ -74,7 +77,7 @@ func TestCreateIdsListError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
@@ -110,11 +113,11 @@ func TestGetColNamesError(t *testing.T) {
 
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -173,11 +176,11 @@ func TestDestHasMoreColsOk(t *testing.T) {
 	dmock.ExpectClose()
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -230,11 +233,11 @@ func TestDestHasLessColsOk(t *testing.T) {
 	dmock.ExpectClose()
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -266,7 +269,7 @@ func TestKeyNotExistsDestError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
@@ -306,11 +309,11 @@ func TestKeyNotExistsSourceError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -350,11 +353,11 @@ func TestPrepareInsertError(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -371,6 +374,49 @@ func TestPrepareInsertError(t *testing.T) {
 	}
 }
 
+func TestPrepareInsertRetryError(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rows := sqlmock.NewRows([]string{"name"}).
+			AddRow("id").
+			AddRow("title").
+			AddRow("body")
+		dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+		rows = sqlmock.NewRows([]string{"count"}).
+			AddRow(0)
+		dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+		dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)").WillReturnError(fmt.Errorf("fake error"))
+	}
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	record := types.Record{"id": "2", "title": "post 2", "body": "world"}
+
+	// The second Save must prepare the statements again instead of using the ones that failed
+	for i := 0; i < 2; i++ {
+		if err = saver.Save(log, record); !errors.Is(err, common.ErrSaverInit) {
+			t.Fatalf("Save should return a preparation error and returned '%v'", err)
+		}
+	}
+
+	if err = dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestPrepareUpdateError(t *testing.T) {
 	sdb, smock, err := sqlmock.New()
 	if err != nil {
@@ -395,16 +441,16 @@ func TestPrepareUpdateError(t *testing.T) {
 	rows = sqlmock.NewRows([]string{"id", "title", "body"})
 	dmock.ExpectQuery("SELECT \\* from \\? LIMIT 1").WithArgs("dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WillReturnError(fmt.Errorf("fake error"))
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WillReturnError(fmt.Errorf("fake error"))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -452,11 +498,11 @@ func TestBeginTransactionError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -512,11 +558,11 @@ func TestEndTransactionError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -531,6 +577,10 @@ func TestEndTransactionError(t *testing.T) {
 			t.Fatalf("Save should not return error and returned '%v'", err)
 		}
 	}
+
+	if err = saver.Close(log); err == nil {
+		t.Errorf("Saver close should return the commit error")
+	}
 }
 
 func TestRollbackTransactionError(t *testing.T) {
@@ -573,11 +623,11 @@ func TestRollbackTransactionError(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -630,17 +680,17 @@ func TestTruncateError(t *testing.T) {
 	rows = sqlmock.NewRows([]string{"count"}).
 		AddRow(0)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( title,body,id\\) VALUES \\( \\?,\\?,\\? \\)")
-	//	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WillReturnError(fmt.Errorf("fake error"))
+	//	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WillReturnError(fmt.Errorf("fake error"))
 	dmock.ExpectQuery("TRUNCATE TABLE dtable").WillReturnError(fmt.Errorf("fake error"))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -678,11 +728,11 @@ func TestColsListError(t *testing.T) {
 	dmock.ExpectQuery("SELECT \\* from \\? LIMIT 1").WithArgs("dtable").WillReturnError(fmt.Errorf("fake error"))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	_, err = database.NewLoader(context.Background(), log, &source, "stable", "")
+	_, err = database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err == nil {
 		t.Fatalf("NewLoader should return error")
 	}
@@ -719,17 +769,17 @@ func TestInsertError(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( title,body,id\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 2", "world", "2").WillReturnError(fmt.Errorf("fake error"))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -777,18 +827,18 @@ func TestDeleteError(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
-	dmock.ExpectExec("UPDATE dtable SET title=\\?,body=\\? WHERE id = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
+	dmock.ExpectExec("UPDATE dtable SET `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("DELETE from dtable WHERE id=1").WillReturnError(fmt.Errorf("fake error"))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func queryForeignKeysByEngine(log *logrus.Entry, engine datasource.Engine, database string, schema string) string {
+	var query string
+
+	switch engine {
+	case datasource.Mysql:
+		query = fmt.Sprintf("SELECT constraint_name, table_name, column_name, referenced_table_name, referenced_column_name FROM information_schema.key_column_usage WHERE table_schema = '%s' AND referenced_table_name IS NOT NULL ORDER BY table_name, constraint_name, ordinal_position;", database) //nolint: gosec
+	case datasource.Postgres:
+		if schema == "" {
+			schema = "public"
+		}
+
+		query = fmt.Sprintf("SELECT kcu.constraint_name, kcu.table_name, kcu.column_name, rkcu.table_name, rkcu.column_name FROM information_schema.referential_constraints rc JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name JOIN information_schema.key_column_usage rkcu ON rkcu.constraint_schema = rc.unique_constraint_schema AND rkcu.constraint_name = rc.unique_constraint_name AND rkcu.ordinal_position = kcu.position_in_unique_constraint WHERE kcu.table_catalog = '%s' AND kcu.table_schema = '%s' ORDER BY kcu.table_name, kcu.constraint_name, kcu.ordinal_position;", database, schema) //nolint: gosec
+	}
+
+	log.Debug(query)
+
+	return query
+}
+
+//ForeignKeys returns the foreign keys between the tables of the database.
+func ForeignKeys(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer) ([]types.ForeignKey, error) {
+	logDb := log.WithField("datasource", ds.GetName())
+	logDb.Debug("Retrieving the foreign keys")
+
+	tv := ds.FillTmplValues()
+	engine, _ := datasource.StringToEngine(tv.Engine)
+
+	db, err := ds.OpenDatabase(logDb, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("can't open %s database : %w", tv.Database, err)
+	}
+
+	defer ds.CloseDatabase(logDb, false, false) //nolint: errcheck
+
+	rows, err := db.QueryContext(ctx, queryForeignKeysByEngine(logDb, engine, tv.Database, tv.Schema))
+	if err != nil {
+		logDb.Error("Querying for retrieving foreign keys failed")
+		logDb.Error(err)
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	fks := make([]types.ForeignKey, 0)
+
+	for rows.Next() {
+		var name, table, column, refTable, refColumn string
+
+		if err := rows.Scan(&name, &table, &column, &refTable, &refColumn); err != nil {
+			logDb.Error("Querying for retrieving foreign keys failed")
+			logDb.Error(err)
+
+			return nil, err
+		}
+
+		// The columns of a multi-columns foreign key are on consecutive rows
+		last := len(fks) - 1
+		if last >= 0 && fks[last].Name == name && fks[last].Table == table {
+			fks[last].Columns = append(fks[last].Columns, column)
+			fks[last].RefColumns = append(fks[last].RefColumns, refColumn)
+
+			continue
+		}
+
+		fks = append(fks, types.ForeignKey{Name: name, Table: table, Columns: []string{column}, RefTable: refTable, RefColumns: []string{refColumn}})
+	}
+
+	return fks, rows.Err()
+}
@@ -0,0 +1,46 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+)
+
+func TestForeignKeysOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"constraint_name", "table_name", "column_name", "referenced_table_name", "referenced_column_name"}).
+		AddRow("fk_lines", "lines", "order_id", "orders", "id").
+		AddRow("fk_stock", "lines", "shop_id", "stocks", "shop_id").
+		AddRow("fk_stock", "lines", "product_id", "stocks", "product_id")
+	mock.ExpectQuery("SELECT constraint_name, table_name, column_name, referenced_table_name, referenced_column_name FROM information_schema.key_column_usage WHERE table_schema = 'blog' AND referenced_table_name IS NOT NULL").WillReturnRows(rows)
+	ds := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	fks, err := database.ForeignKeys(context.Background(), log, &ds)
+	if err != nil {
+		t.Fatalf("ForeignKeys should not return error and returned '%v'", err)
+	}
+
+	if len(fks) != 2 {
+		t.Fatalf("ForeignKeys should return 2 foreign keys, returned %v", fks)
+	}
+
+	if fks[1].Name != "fk_stock" || len(fks[1].Columns) != 2 || fks[1].RefColumns[1] != "product_id" || fks[1].RefTable != "stocks" {
+		t.Errorf("The multi-columns foreign key is wrong: %v", fks[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
@@ -13,18 +14,67 @@ import (
 
 //DbLoader specifc state for database Loader provider.
 type DbLoader struct {
-	ds       datasource.Datasourcer
-	db       *sql.DB
-	database string
-	table    string
-	rows     *sql.Rows
-	scanned  []interface{}
-	rawBytes []sql.NullString
-	colNames []string
+	ctx         context.Context
+	log         *logrus.Entry
+	ds          datasource.Datasourcer
+	db          *sql.DB
+	engine      datasource.Engine
+	database    string
+	schema      string
+	rawtable    string
+	table       string
+	where       string
+	options     types.LoaderOptions
+	rows        *sql.Rows
+	scanned     []interface{}
+	rawBytes    []sql.NullString
+	colNames    []string
+	columnTypes []*sql.ColumnType
+	primaryKeys []string
+	keyIndex    int   // Index of the OrderBy column in the record
+	chunkCount  int   // Number of records read in the current chunk
+	err         error // Error encountered by Next, returned by the following Load
+}
+
+//loadQuery return the data query and its arguments, the records are sorted and limited to the ones after a value of the sorting column if asked.
+func loadQuery(engine datasource.Engine, table string, where string, options types.LoaderOptions) (string, []interface{}) {
+	args := make([]interface{}, 0, 1)
+
+	if options.OrderBy != "" && options.After != "" {
+		placeholder := "?"
+		if engine == datasource.Postgres {
+			placeholder = "$1"
+		}
+
+		after := fmt.Sprintf("%s > %s", options.OrderBy, placeholder)
+		if where != "" {
+			where = fmt.Sprintf("(%s) AND %s", where, after)
+		} else {
+			where = after
+		}
+
+		args = append(args, options.After)
+	}
+
+	if where != "" {
+		where = fmt.Sprintf("WHERE %s", where) //nolint:gosec
+	}
+
+	query := fmt.Sprintf("SELECT * from %s %s", table, where) //nolint:gosec
+
+	if options.OrderBy != "" {
+		query = fmt.Sprintf("%s ORDER BY %s", query, options.OrderBy)
+	}
+
+	if options.ChunkSize > 0 {
+		query = fmt.Sprintf("%s LIMIT %d", query, options.ChunkSize)
+	}
+
+	return query, args
 }
 
 //NewLoader open the database connection, make the data query and return a Loader compatible object.
-func NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (*DbLoader, error) {
+func NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string, options types.LoaderOptions) (*DbLoader, error) {
 	logDb := log.WithField("datasource", ds.GetName())
 
 	if table == "" {
@@ -33,71 +83,172 @@ func NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer
 	}
 
 	tv := ds.FillTmplValues()
+	rawtable := table
+	engine, _ := datasource.StringToEngine(tv.Engine)
 
 	if tv.Schema != "" {
 		table = fmt.Sprintf("%s.%s", tv.Schema, table)
 	}
 
-	if where != "" {
-		where = fmt.Sprintf("WHERE %s", where) //nolint:gosec
-	}
-
 	db, err := ds.OpenDatabase(logDb, false, false)
 	if err != nil {
 		return nil, fmt.Errorf("can't open %s database : %w", tv.Database, err)
 	}
 
-	logDb.Debugf("Load query: SELECT * from %s %s", table, where)
+	dl := &DbLoader{
+		ctx:      ctx,
+		log:      logDb,
+		ds:       ds,
+		db:       db,
+		engine:   engine,
+		database: tv.Database,
+		schema:   tv.Schema,
+		rawtable: rawtable,
+		table:    table,
+		where:    where,
+		options:  options,
+		keyIndex: -1,
+	}
 
-	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT * from %s %s", table, where)) //nolint:gosec
-	if err != nil || rows.Err() != nil {
-		logDb.Error("Source query failed")
-		logDb.Error(err)
+	// The chunks are paginated by the primary key if no sorting column has been provided
+	if options.ChunkSize > 0 && options.OrderBy == "" {
+		keys, err := dl.getPrimaryKeys(logDb)
+		if err != nil {
+			return nil, err
+		}
 
+		if len(keys) != 1 {
+			logDb.Errorf("Chunked read of %s needs a primary key of one column", table)
+			return nil, fmt.Errorf("chunked read of %s needs a primary key of one column: %w", table, common.ErrWrongParameterValue)
+		}
+
+		dl.options.OrderBy = keys[0]
+	}
+
+	if err = dl.query(logDb); err != nil {
 		return nil, err
 	}
 
+	return dl, nil
+}
+
+//query make the data query for the records after the last one read.
+func (dl *DbLoader) query(log *logrus.Entry) error {
+	query, args := loadQuery(dl.engine, dl.table, dl.where, dl.options)
+
+	log.Debugf("Load query: %s", query)
+
+	rows, err := dl.db.QueryContext(dl.ctx, query, args...)
+	if err != nil || rows.Err() != nil {
+		log.Error("Source query failed")
+		log.Error(err)
+
+		return err
+	}
+
+	dl.rows = rows
+	dl.chunkCount = 0
+
+	if dl.colNames != nil {
+		return nil
+	}
+
 	columns, err := rows.ColumnTypes()
 	if err != nil {
-		logDb.Error("Determining column names failed")
-		logDb.Error(err)
+		log.Error("Determining column names failed")
+		log.Error(err)
 
-		return nil, err
+		return err
 	}
 
-	columnsname := make([]string, len(columns))
+	dl.colNames = make([]string, len(columns))
 
 	for i, col := range columns {
-		columnsname[i] = col.Name()
+		dl.colNames[i] = col.Name()
+		if col.Name() == dl.options.OrderBy {
+			dl.keyIndex = i
+		}
+	}
+
+	if dl.options.ChunkSize > 0 && dl.keyIndex == -1 {
+		log.Errorf("Column %s used for chunked read not found in %s", dl.options.OrderBy, dl.table)
+		return fmt.Errorf("column %s used for chunked read not found in %s: %w", dl.options.OrderBy, dl.table, common.ErrWrongParameterValue)
 	}
 
-	rawBytes := make([]sql.NullString, len(columns)) // Buffers for each column
-	scanned := make([]interface{}, len(columns))     // Address of each Buffers since sql.QueryContext needs pointer to each column buffers
+	dl.rawBytes = make([]sql.NullString, len(columns)) // Buffers for each column
+	dl.scanned = make([]interface{}, len(columns))     // Address of each Buffers since sql.QueryContext needs pointer to each column buffers
 
-	for i := range rawBytes {
-		scanned[i] = &rawBytes[i]
+	for i := range dl.rawBytes {
+		dl.scanned[i] = &dl.rawBytes[i]
 	}
 
-	return &DbLoader{
-		ds:       ds,
-		db:       db,
-		database: tv.Database,
-		table:    table,
-		rows:     rows,
-		scanned:  scanned,
-		rawBytes: rawBytes,
-		colNames: columnsname}, nil
+	dl.columnTypes = columns
+
+	return nil
+}
+
+//nextChunk close the current chunk and query the following one, return false if there is no more chunk.
+func (dl *DbLoader) nextChunk() bool {
+	if err := dl.rows.Close(); err != nil {
+		dl.err = err
+		return true
+	}
+
+	// The last chunk was not full, there is no more records
+	if dl.chunkCount < dl.options.ChunkSize {
+		return false
+	}
+
+	if dl.options.ChunkPause > 0 {
+		select {
+		case <-dl.ctx.Done():
+			return false
+		case <-time.After(dl.options.ChunkPause):
+		}
+	}
+
+	if err := dl.query(dl.log); err != nil {
+		dl.err = err
+		return true
+	}
+
+	return dl.rows.Next()
 }
 
 //Next moves to next record and return false if there is no more records.
 func (dl *DbLoader) Next() bool {
-	return dl.rows.Next()
+	if dl.err != nil {
+		return true
+	}
+
+	if dl.rows.Next() {
+		return true
+	}
+
+	if dl.options.ChunkSize <= 0 {
+		return false
+	}
+
+	// Rows.Next returns false on error, Load will report it
+	if err := dl.rows.Err(); err != nil {
+		dl.err = err
+		return true
+	}
+
+	return dl.nextChunk()
 }
 
 //Load reads the next record and return it.
 func (dl *DbLoader) Load(log *logrus.Entry) (types.Record, error) {
 	logDb := log.WithField("datasource", dl.ds.GetName())
 
+	if dl.err != nil {
+		logDb.Error("Getting next row failed")
+		logDb.Error(dl.err)
+
+		return nil, dl.err
+	}
+
 	err := dl.rows.Scan(dl.scanned...)
 	if err != nil {
 		logDb.Error("Getting next row failed")
@@ -116,6 +267,11 @@ func (dl *DbLoader) Load(log *logrus.Entry) (types.Record, error) {
 
 	record := make(types.Record, len(dl.colNames))
 
+	if dl.options.ChunkSize > 0 {
+		dl.chunkCount++
+		dl.options.After = dl.rawBytes[dl.keyIndex].String
+	}
+
 	for i, col := range dl.colNames {
 		if dl.rawBytes[i].Valid {
 			record[col] = dl.rawBytes[i].String
@@ -151,3 +307,136 @@ func (dl *DbLoader) Close(log *logrus.Entry) error {
 func (dl *DbLoader) Name() string {
 	return dl.database + "_" + dl.table
 }
+
+func (dl *DbLoader) queryPrimaryKeyByEngine(log *logrus.Entry) string {
+	var query string
+
+	switch dl.engine {
+	case datasource.Mysql:
+		query = fmt.Sprintf("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = '%s' AND table_name = '%s' AND constraint_name = 'PRIMARY';", dl.database, dl.rawtable) //nolint: gosec
+	case datasource.Postgres:
+		schema := "public"
+		if dl.schema != "" {
+			schema = dl.schema
+		}
+
+		query = fmt.Sprintf("SELECT kcu.column_name FROM information_schema.table_constraints tc JOIN information_schema.key_column_usage kcu ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_catalog = '%s' AND tc.table_schema = '%s' AND tc.table_name = '%s';", dl.database, schema, dl.rawtable) //nolint: gosec
+	}
+
+	log.Debug(query)
+
+	return query
+}
+
+//getPrimaryKeys returns the columns of the primary key of the source table.
+func (dl *DbLoader) getPrimaryKeys(log *logrus.Entry) ([]string, error) {
+	if dl.primaryKeys != nil {
+		return dl.primaryKeys, nil
+	}
+
+	log.Debug("Retrieving the primary key columns")
+
+	rows, err := dl.db.QueryContext(dl.ctx, dl.queryPrimaryKeyByEngine(log))
+	if err != nil {
+		log.Error("Querying for retrieving primary key columns failed")
+		log.Error(err)
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 1)
+
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			log.Error("Querying for retrieving primary key columns failed")
+			log.Error(err)
+
+			return nil, err
+		}
+
+		keys = append(keys, col)
+	}
+
+	dl.primaryKeys = keys
+
+	return keys, nil
+}
+
+//Columns returns the description of the columns of the source table.
+func (dl *DbLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	logDb := log.WithField("datasource", dl.ds.GetName())
+
+	keys, err := dl.getPrimaryKeys(logDb)
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKeys := make(map[string]bool)
+	for _, key := range keys {
+		primaryKeys[key] = true
+	}
+
+	columns := make([]types.Column, 0, len(dl.columnTypes))
+
+	for _, ct := range dl.columnTypes {
+		col := types.Column{
+			Name:       ct.Name(),
+			Type:       genericType(dl.engine, ct.DatabaseTypeName()),
+			Nullable:   true,
+			PrimaryKey: primaryKeys[ct.Name()],
+		}
+
+		if nullable, ok := ct.Nullable(); ok {
+			col.Nullable = nullable
+		}
+
+		if length, ok := ct.Length(); ok {
+			col.Length = length
+		}
+
+		if precision, scale, ok := ct.DecimalSize(); ok {
+			col.Precision = precision
+			col.Scale = scale
+		}
+
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+//Count returns the number of records that a loader would read.
+func Count(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (int64, error) {
+	logDb := log.WithField("datasource", ds.GetName())
+
+	tv := ds.FillTmplValues()
+	if tv.Schema != "" {
+		table = fmt.Sprintf("%s.%s", tv.Schema, table)
+	}
+
+	db, err := ds.OpenDatabase(logDb, false, false)
+	if err != nil {
+		return 0, fmt.Errorf("can't open %s database : %w", tv.Database, err)
+	}
+
+	defer ds.CloseDatabase(logDb, false, false) //nolint: errcheck
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", table) //nolint: gosec
+	if where != "" {
+		query = fmt.Sprintf("%s WHERE %s", query, where)
+	}
+
+	logDb.Debugf("Count query: %s", query)
+
+	var count int64
+	if err = db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+		logDb.Error("Counting source rows failed")
+		logDb.Error(err)
+
+		return 0, err
+	}
+
+	return count, nil
+}
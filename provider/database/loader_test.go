@@ -0,0 +1,172 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestLoadAfterOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title"}).
+		AddRow(3, "post 3").
+		AddRow(4, "post 4")
+	mock.ExpectQuery("SELECT \\* from stable WHERE \\(title != ''\\) AND id > \\$1 ORDER BY id").WithArgs("2").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title != ''", types.LoaderOptions{OrderBy: "id", After: "2"})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	count := 0
+
+	for loader.Next() {
+		if _, err := loader.Load(log); err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("Load should return 2 records, returned %d", count)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+}
+
+func TestLoadChunkOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"column_name"}).
+		AddRow("id")
+	mock.ExpectQuery("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = 'blog' AND table_name = 'stable' AND constraint_name = 'PRIMARY';").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"id", "title"}).
+		AddRow(1, "post 1").
+		AddRow(2, "post 2")
+	mock.ExpectQuery("SELECT \\* from stable  ORDER BY id LIMIT 2").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"id", "title"}).
+		AddRow(3, "post 3").
+		AddRow(4, "post 4")
+	mock.ExpectQuery("SELECT \\* from stable WHERE id > \\? ORDER BY id LIMIT 2").WithArgs("2").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"id", "title"})
+	mock.ExpectQuery("SELECT \\* from stable WHERE id > \\? ORDER BY id LIMIT 2").WithArgs("4").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{ChunkSize: 2})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	ids := ""
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		ids += record["id"]
+	}
+
+	if ids != "1234" {
+		t.Errorf("Load should return the records 1 to 4, returned %s", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+}
+
+func TestLoadChunkNoKeyError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"column_name"})
+	mock.ExpectQuery("SELECT column_name FROM information_schema.key_column_usage").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err = database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{ChunkSize: 2})
+	if err == nil {
+		t.Errorf("NewLoader should return error")
+	}
+}
+
+func TestCountOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title"}).
+		AddRow(1, "post 1").
+		AddRow(2, "post 2")
+	mock.ExpectQuery("SELECT \\* from blog.stable WHERE title != ''").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(2)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM blog.stable WHERE title != ''").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog", Schema: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title != ''", types.LoaderOptions{})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	// The count is done while the source is opened, like the progression does
+	count, err := database.Count(context.Background(), log, &source, "stable", "title != ''")
+	if err != nil {
+		t.Fatalf("Count should not return error and returned '%v'", err)
+	}
+
+	if count != 2 {
+		t.Errorf("Count should return 2, returned %d", count)
+	}
+
+	loaded := 0
+
+	for loader.Next() {
+		if _, err := loader.Load(log); err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		loaded++
+	}
+
+	if loaded != 2 {
+		t.Errorf("Load should return 2 records after the count, returned %d", loaded)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//DbLookup specifc state for database Lookup provider.
+type DbLookup struct {
+	ctx  context.Context
+	ds   datasource.Datasourcer
+	conn *sql.Conn
+	stmt *sql.Stmt
+	key  string
+}
+
+//NewLookup open the database connection, prepare the query of the records by key value and return a Lookup compatible object.
+func NewLookup(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string) (*DbLookup, error) {
+	logDb := log.WithField("datasource", ds.GetName())
+
+	tv := ds.FillTmplValues()
+	if tv.Schema != "" {
+		table = fmt.Sprintf("%s.%s", tv.Schema, table)
+	}
+
+	placeholder := "?"
+	if ds.GetEngine() == datasource.Postgres {
+		placeholder = "$1"
+	}
+
+	db, err := ds.OpenDatabase(logDb, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("can't open %s database : %w", tv.Database, err)
+	}
+
+	query := fmt.Sprintf("SELECT * from %s WHERE %s = %s", table, key, placeholder) //nolint: gosec
+	logDb.Debugf("Lookup query: %s", query)
+
+	// The statement is prepared on a connection kept by the lookup, the pool would prepare it again on each connection used
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		logDb.Error("Getting a connection for the lookup failed")
+		logDb.Error(err)
+		ds.CloseDatabase(logDb, false, false) //nolint: errcheck
+
+		return nil, err
+	}
+
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		logDb.Error("Preparing the lookup query failed")
+		logDb.Error(err)
+		conn.Close()
+		ds.CloseDatabase(logDb, false, false) //nolint: errcheck
+
+		return nil, err
+	}
+
+	return &DbLookup{ctx: ctx, ds: ds, conn: conn, stmt: stmt, key: key}, nil
+}
+
+//Fetch return the first record having the key value, nil if there is none.
+func (dl *DbLookup) Fetch(log *logrus.Entry, value string) (types.Record, error) {
+	rows, err := dl.stmt.QueryContext(dl.ctx, value)
+	if err != nil {
+		log.Error("Querying the lookup datasource failed")
+		log.Error(err)
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	colNames, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]sql.NullString, len(colNames))
+	pointers := make([]interface{}, len(colNames))
+
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		record := make(types.Record, len(colNames))
+
+		for i, col := range colNames {
+			if values[i].Valid {
+				record[col] = values[i].String
+			} else {
+				record[col] = types.NullValue
+			}
+		}
+
+		// The database could have converted the value to compare it, only the exact matches are kept
+		if record[dl.key] == value {
+			return record, nil
+		}
+	}
+
+	return nil, rows.Err()
+}
+
+//Close closes the statement, gives back its connection and closes the database.
+func (dl *DbLookup) Close(log *logrus.Entry) error {
+	logDb := log.WithField("datasource", dl.ds.GetName())
+
+	if err := dl.stmt.Close(); err != nil {
+		logDb.Error("Closing the lookup query failed")
+		logDb.Error(err)
+	}
+
+	if err := dl.conn.Close(); err != nil {
+		logDb.Error("Closing the lookup connection failed")
+		logDb.Error(err)
+	}
+
+	return dl.ds.CloseDatabase(logDb, false, false)
+}
@@ -0,0 +1,90 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestLookupOk(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectPrepare("SELECT \\* from public.types WHERE code = \\$1")
+
+	rows := sqlmock.NewRows([]string{"code", "label"}).
+		AddRow("e", "case insensitive").
+		AddRow("E", nil)
+	mock.ExpectQuery("SELECT \\* from public.types WHERE code = \\$1").WithArgs("E").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"code", "label"})
+	mock.ExpectQuery("SELECT \\* from public.types WHERE code = \\$1").WithArgs("it's").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT \\* from public.types WHERE code = \\$1").WithArgs("W").WillReturnError(fmt.Errorf("fake error"))
+
+	ds := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog", Schema: "public"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	lookup, err := database.NewLookup(context.Background(), log, &ds, "types", "code")
+	if err != nil {
+		t.Fatalf("NewLookup should not return error and returned '%v'", err)
+	}
+
+	// Only the exact match is kept
+	record, err := lookup.Fetch(log, "E")
+	if err != nil {
+		t.Fatalf("Fetch should not return error and returned '%v'", err)
+	}
+
+	if record["code"] != "E" || record["label"] != types.NullValue {
+		t.Errorf("Fetch should return the record with the exact key, returned %v", record)
+	}
+
+	if record, err = lookup.Fetch(log, "it's"); err != nil || record != nil {
+		t.Errorf("Fetch should return nothing for a missing key, returned %v, %v", record, err)
+	}
+
+	if _, err = lookup.Fetch(log, "W"); err == nil {
+		t.Errorf("Fetch should return error")
+	}
+
+	if db.Stats().InUse != 1 {
+		t.Errorf("The lookup should keep one connection, %d are in use", db.Stats().InUse)
+	}
+
+	if err = lookup.Close(log); err != nil {
+		t.Errorf("Close should not return error and returned '%v'", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Lookup: %s", err)
+	}
+}
+
+func TestLookupPrepareError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	mock.ExpectPrepare("SELECT \\* from types WHERE code = \\?").WillReturnError(fmt.Errorf("fake error"))
+
+	ds := mockdatasource.MockDatasource{MockedDb: db, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	if _, err = database.NewLookup(context.Background(), log, &ds, "types", "code"); err == nil {
+		t.Errorf("NewLookup should return error")
+	}
+}
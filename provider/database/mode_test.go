@@ -9,6 +9,7 @@ import (
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/mockdatasource"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestOnlyIfEmptyOk(t *testing.T) {
@@ -44,7 +45,7 @@ func TestOnlyIfEmptyOk(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saverfull, err := database.NewSaver(context.Background(), log, &destfull, "dtable", "id", "onlyIfEmpty")
+	saverfull, err := database.NewSaver(context.Background(), log, &destfull, "dtable", "id", "onlyIfEmpty", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
@@ -67,11 +68,11 @@ func TestOnlyIfEmptyOk(t *testing.T) {
 	dmockempty.ExpectExec("INSERT INTO dtable").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	destempty := mockdatasource.MockDatasource{MockedDb: ddbempty, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 
-	saverempty, err := database.NewSaver(context.Background(), log, &destempty, "dtable", "id", "onlyIfEmpty")
+	saverempty, err := database.NewSaver(context.Background(), log, &destempty, "dtable", "id", "onlyIfEmpty", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -151,11 +152,11 @@ func TestInsertOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -226,11 +227,11 @@ func TestTruncateOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -303,11 +304,11 @@ func TestTruncateTransactionOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -342,6 +343,53 @@ func TestTruncateTransactionOk(t *testing.T) {
 
 }
 
+func TestTruncateBeforeSaveOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	dmock.ExpectExec("TRUNCATE TABLE dtable").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`id`\\) VALUES \\( \\?,\\? \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectCommit()
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog", Transaction: true}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "truncate", types.SaverOptions{})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Truncate(log); err != nil {
+		t.Fatalf("Truncate should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "1", "title": "post 1"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Close(log); err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
 func TestUpdateOk(t *testing.T) {
 	sdb, smock, err := sqlmock.New()
 	if err != nil {
@@ -377,18 +425,18 @@ func TestUpdateOk(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
-	dmock.ExpectExec("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
-	dmock.ExpectExec("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "update", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -457,18 +505,102 @@ func TestReplaceOk(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace", types.SaverOptions{})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		if err = saver.Save(log, record); err != nil {
+			t.Fatalf("Save should not return error and returned '%v'", err)
+		}
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	err = loader.Close(log)
+	if err != nil {
+		t.Errorf("Loader close should not return error and returned '%v'", err)
+	}
+
+	if err := smock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+
+}
+
+func TestMergeOk(t *testing.T) {
+	sdb, smock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "body"}).
+		AddRow(1, "post 1", "hello").
+		AddRow(2, "post 2", "world")
+
+	smock.ExpectQuery("SELECT (.+) from stable").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: sdb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	rows = sqlmock.NewRows([]string{"id"}).
+		AddRow(3).
+		AddRow(2)
+	dmock.ExpectQuery("SELECT id from dtable").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"id", "title", "body"}).
+		AddRow(3, "post 3", "good").
+		AddRow(2, "post 2 bis", "planet")
+	rows = sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title").
+		AddRow("body")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
+	dmock.ExpectPrepare("SELECT `title`,`body`,`id` FROM dtable WHERE `id` = \\?")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
-	dmock.ExpectExec("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	rows = sqlmock.NewRows([]string{"title", "body", "id"}).
+		AddRow("post 2 bis", "planet", 2)
+	dmock.ExpectQuery("SELECT `title`,`body`,`id` FROM dtable WHERE `id` = \\?").WithArgs("2").WillReturnRows(rows)
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "planet", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "merge", types.SaverOptions{Merge: types.MergePolicy{Policy: "source", Columns: map[string]string{"body": "destination"}}})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -538,19 +670,101 @@ func TestExactCopyOk(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
-	dmock.ExpectExec("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("DELETE from \\? WHERE \\?=\\?").WithArgs("dtable", "id", "3").WillReturnResult(sqlmock.NewResult(1, 1))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		if err = saver.Save(log, record); err != nil {
+			t.Fatalf("Save should not return error and returned '%v'", err)
+		}
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	err = loader.Close(log)
+	if err != nil {
+		t.Errorf("Loader close should not return error and returned '%v'", err)
+	}
+
+	if err := smock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+
+}
+
+func TestExactCopySoftDeleteOk(t *testing.T) {
+	sdb, smock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "body"}).
+		AddRow(1, "post 1", "hello").
+		AddRow(2, "post 2", "world")
+
+	smock.ExpectQuery("SELECT (.+) from stable").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: sdb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows = sqlmock.NewRows([]string{"id"}).
+		AddRow(3).
+		AddRow(2)
+	dmock.ExpectQuery("SELECT id from dtable").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"id", "title", "body"}).
+		AddRow(3, "post 3", "good").
+		AddRow(2, "post 2 bis", "planet")
+	rows = sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title").
+		AddRow("body")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`,`deleted_at`\\) VALUES \\( \\?,\\?,\\?,NULL \\)")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\?,`deleted_at`=NULL WHERE `id` = \\?")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\?,`deleted_at`=NULL WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").WithArgs("3").WillReturnResult(sqlmock.NewResult(1, 1))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{SoftDelete: types.SoftDelete{Column: "deleted_at", Deleted: "CURRENT_TIMESTAMP", Alive: "NULL"}})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -585,6 +799,37 @@ func TestExactCopyOk(t *testing.T) {
 
 }
 
+func TestExactCopySoftDeleteEmptySourceOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow(3)
+	dmock.ExpectQuery("SELECT id from dtable").WillReturnRows(rows)
+	dmock.ExpectBegin()
+	dmock.ExpectExec("UPDATE dtable SET deleted_at = CURRENT_TIMESTAMP WHERE id = \\? AND deleted_at IS NULL").WithArgs("3").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectCommit()
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog", Transaction: true}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{SoftDelete: types.SoftDelete{Column: "deleted_at", Deleted: "CURRENT_TIMESTAMP", Alive: "NULL"}})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Close(log); err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
 func TestExactCopyTransactionOk(t *testing.T) {
 	sdb, smock, err := sqlmock.New()
 	if err != nil {
@@ -621,20 +866,20 @@ func TestExactCopyTransactionOk(t *testing.T) {
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
 	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
-	dmock.ExpectPrepare("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?")
+	dmock.ExpectPrepare("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
-	dmock.ExpectExec("UPDATE dtable SET  title=\\?,body=\\? WHERE id = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("UPDATE dtable SET  `title`=\\?,`body`=\\? WHERE `id` = \\?").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("DELETE from \\? WHERE \\?=\\?").WithArgs("dtable", "id", "3").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectCommit()
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog", Transaction: true}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "exactCopy", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/mockdatasource"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestMySqlOk(t *testing.T) {
@@ -45,11 +46,11 @@ func TestMySqlOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -135,11 +136,11 @@ func TestMySqlTransactionOk(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/mockdatasource"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestOpenError(t *testing.T) {
@@ -28,12 +29,12 @@ func TestOpenError(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace")
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace", types.SaverOptions{})
 	if err == nil {
 		t.Fatalf("NewSaver should return error")
 	}
 
-	_, err = database.NewLoader(context.Background(), log, &source, "stable", "")
+	_, err = database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err == nil {
 		t.Fatalf("NewLoader should return error")
 	}
@@ -74,11 +75,11 @@ func TestResetOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "title like '%'", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -163,11 +164,11 @@ func TestResetTransactionOk(t *testing.T) {
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
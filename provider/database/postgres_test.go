@@ -2,6 +2,7 @@ package database_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -9,6 +10,7 @@ import (
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/mockdatasource"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
 )
 
 func TestPostgresOk(t *testing.T) {
@@ -38,18 +40,18 @@ func TestPostgresOk(t *testing.T) {
 	rows = sqlmock.NewRows([]string{"count"}).
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
-	dmock.ExpectPrepare("INSERT INTO dtable \\( title,body,id\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"body\",\"id\"\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -126,7 +128,7 @@ func TestPostgresTransactionOk(t *testing.T) {
 	rows = sqlmock.NewRows([]string{"count"}).
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
-	dmock.ExpectPrepare("INSERT INTO dtable \\( title,body,id\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"body\",\"id\"\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectCommit()
@@ -135,11 +137,11 @@ func TestPostgresTransactionOk(t *testing.T) {
 	log := logger.WithField("appname", "kamino")
 	logger.SetLevel(logrus.PanicLevel)
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -215,18 +217,18 @@ func TestPostgresSchemaOk(t *testing.T) {
 	rows = sqlmock.NewRows([]string{"count"}).
 		AddRow(0)
 	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM greatbob.dtable").WillReturnRows(rows)
-	dmock.ExpectPrepare("INSERT INTO greatbob.dtable \\( title,body,id\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
+	dmock.ExpectPrepare("INSERT INTO greatbob.dtable \\( \"title\",\"body\",\"id\"\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
 	dmock.ExpectExec("INSERT INTO greatbob.dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
 	dmock.ExpectExec("INSERT INTO greatbob.dtable").WithArgs("post 2", "world", "2").WillReturnResult(sqlmock.NewResult(1, 1))
 	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog", Schema: "greatbob"}
 	logger := logrus.New()
 	log := logger.WithField("appname", "kamino")
 
-	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert")
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{})
 	if err != nil {
 		t.Fatalf("NewSaver should not return error and returned '%v'", err)
 	}
-	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "")
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
 	if err != nil {
 		t.Fatalf("NewLoader should not return error and returned '%v'", err)
 	}
@@ -274,3 +276,55 @@ func TestPostgresSchemaOk(t *testing.T) {
 	}
 
 }
+
+func TestSavepointPostgresOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dmock.ExpectBegin()
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"id\"\\) VALUES \\( \\$1,\\$2 \\)")
+	dmock.ExpectExec("SAVEPOINT kamino_row").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "1").WillReturnError(fmt.Errorf("fake error"))
+	dmock.ExpectExec("ROLLBACK TO SAVEPOINT kamino_row").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectExec("SAVEPOINT kamino_row").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 2", "2").WillReturnResult(sqlmock.NewResult(1, 1))
+	dmock.ExpectExec("RELEASE SAVEPOINT kamino_row").WillReturnResult(sqlmock.NewResult(0, 0))
+	dmock.ExpectCommit()
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog", Transaction: true}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{TolerateErrors: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "1", "title": "post 1"}); err == nil {
+		t.Errorf("Save should return error")
+	}
+
+	// The failing record did not abort the transaction
+	if err = saver.Save(log, types.Record{"id": "2", "title": "post 2"}); err != nil {
+		t.Errorf("Save should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Close(log); err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
@@ -20,33 +21,46 @@ const (
 	replace     dbSaverMode = iota // Will update if line with same primary exist or insert the line
 	exactCopy   dbSaverMode = iota // As replace but will remove line with primary key not present in source
 	truncate    dbSaverMode = iota // As insert but will truncate the table before
+	merge       dbSaverMode = iota // As replace but the existing lines are merged following a policy
 )
 
 //DbSaver specifc state for database Saver provider.
 type DbSaver struct {
-	ds           datasource.Datasourcer
-	db           *sql.DB
-	tx           *sql.Tx
-	database     string
-	table        string
-	rawtable     string
-	schema       string
-	insertString string
-	insertStmt   *sql.Stmt
-	updateString string
-	updateStmt   *sql.Stmt
-	colNames     []string
-	mode         dbSaverMode
-	wasEmpty     bool
-	key          string
-	transaction  bool
-	engine       datasource.Engine
-	ids          map[string]bool
-	ctx          context.Context
+	ds              datasource.Datasourcer
+	db              *sql.DB
+	tx              *sql.Tx
+	database        string
+	table           string
+	rawtable        string
+	schema          string
+	insertString    string
+	insertStmt      *sql.Stmt
+	updateString    string
+	updateStmt      *sql.Stmt
+	selectString    string
+	selectStmt      *sql.Stmt
+	mergePolicy     types.MergePolicy
+	softDelete      types.SoftDelete
+	resetSeq        bool
+	disableFKs      bool
+	disableTriggers bool
+	checksDisabled  bool
+	truncated       bool
+	savepoint       bool
+	colNames        []string
+	mode            dbSaverMode
+	wasEmpty        bool
+	key             string
+	transaction     bool
+	engine          datasource.Engine
+	ids             map[string]bool
+	ctx             context.Context
+	createTable     bool
+	columns         []types.Column
 }
 
 //NewSaver open the database connection, prepare the insert statement and return a Saver compatible object.
-func NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string) (*DbSaver, error) {
+func NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string, options types.SaverOptions) (*DbSaver, error) {
 	logDb := log.WithField("datasource", ds.GetName())
 	tv := ds.FillTmplValues()
 
@@ -80,15 +94,45 @@ func NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer,
 	saver.db = db
 	saver.key = key
 	saver.mode = stringToMode(mode)
+	saver.mergePolicy = options.Merge
+	saver.softDelete = options.SoftDelete
+	saver.resetSeq = options.ResetSequence
+	saver.disableFKs = options.DisableForeignKeys
+	saver.disableTriggers = options.DisableTriggers
+	// On Postgres, a failing statement aborts the whole transaction, each row is protected by a savepoint
+	saver.savepoint = options.TolerateErrors && saver.transaction && saver.engine == datasource.Postgres
+
+	if err := saver.checkOptions(logDb); err != nil {
+		return nil, err
+	}
 
 	saver.ids = make(map[string]bool)
 
-	if saver.mode == replace || saver.mode == exactCopy || saver.mode == update {
+	if saver.keyed() {
 		if saver.key == "" {
-			logDb.Errorf("Modes replace and exactCopy need a primary key for %s.%s", saver.database, saver.table)
-			return nil, fmt.Errorf("modes replace and exactCopy need a primary key for %s.%s: %w", saver.database, saver.table, common.ErrMissingParameter)
+			logDb.Errorf("Modes replace, merge and exactCopy need a primary key for %s.%s", saver.database, saver.table)
+			return nil, fmt.Errorf("modes replace, merge and exactCopy need a primary key for %s.%s: %w", saver.database, saver.table, common.ErrMissingParameter)
+		}
+	}
+
+	if saver.resetSeq && saver.key == "" {
+		logDb.Errorf("Sequence reset needs a primary key for %s.%s", saver.database, saver.table)
+		return nil, fmt.Errorf("sequence reset needs a primary key for %s.%s: %w", saver.database, saver.table, common.ErrMissingParameter)
+	}
+
+	newTable := false
+
+	if options.CreateTable {
+		saver.columns = options.Columns
+
+		newTable, err = saver.prepareTableCreation(logDb)
+		if err != nil {
+			return nil, err
 		}
+	}
 
+	// If the table has just been created (or will be at first save), there is no IDs to retrieve
+	if !newTable && saver.keyed() {
 		logDb.Debug("Create current IDs list")
 
 		err = saver.createIdsList(logDb)
@@ -103,52 +147,121 @@ func NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer,
 	return &saver, nil
 }
 
+//keyed return true if the mode needs to know the keys already present in the destination.
+func (saver *DbSaver) keyed() bool {
+	return saver.mode == replace || saver.mode == exactCopy || saver.mode == update || saver.mode == merge
+}
+
 func (saver *DbSaver) initSave(log *logrus.Entry, record types.Record) error {
 	log.Debug("First Save action, preparing the needed informations")
 
 	var err error
 
-	if saver.transaction {
-		log.Debug("Starting transaction")
-
-		saver.tx, err = saver.db.Begin()
+	// The table creation is done outside of the transaction since some engines does not support DDL in transaction
+	if saver.createTable {
+		err = saver.createDestTable(log, record)
 		if err != nil {
-			log.Error("Beginning transaction failed")
-			log.Error(err)
-
 			return err
 		}
 	}
 
+	err = saver.begin(log)
+	if err != nil {
+		return err
+	}
+
 	log.Debug("Preparing the statements")
 
 	err = saver.createStatement(log, record)
 	if err != nil {
 		return err
 	}
+
 	// The truncate will be done at the first record save to avoid truncate a table if there is an error on config file
-	if saver.mode == truncate {
-		log.Debug("Truncating the destination table")
+	return saver.Truncate(log)
+}
 
-		if saver.transaction {
-			_, err = saver.tx.Exec(fmt.Sprintf("TRUNCATE TABLE %s", saver.table))
-		} else {
-			_, err = saver.db.Exec(fmt.Sprintf("TRUNCATE TABLE %s", saver.table))
-		}
+//begin starts the transaction, if the saver uses one and it is not already started.
+func (saver *DbSaver) begin(log *logrus.Entry) error {
+	if !saver.transaction || saver.tx != nil {
+		return nil
+	}
 
-		if err != nil {
-			log.Error("Truncating the destination table failed")
-			log.Error(err)
+	log.Debug("Starting transaction")
 
-			return err
-		}
+	var err error
+
+	saver.tx, err = saver.db.Begin()
+	if err != nil {
+		log.Error("Beginning transaction failed")
+		log.Error(err)
+
+		return err
+	}
+
+	return saver.disableChecks(log)
+}
+
+//Truncate empties the destination table in truncate mode, if it has not already been done.
+//It is called by the first Save, but can be called before to choose the order in which several tables are truncated.
+func (saver *DbSaver) Truncate(log *logrus.Entry) error {
+	if saver.mode != truncate || saver.truncated || saver.createTable {
+		return nil
+	}
+
+	if err := saver.begin(log); err != nil {
+		return err
+	}
+
+	log.Debug("Truncating the destination table")
+
+	var err error
+
+	if saver.transaction {
+		_, err = saver.tx.Exec(fmt.Sprintf("TRUNCATE TABLE %s", saver.table))
+	} else {
+		_, err = saver.db.Exec(fmt.Sprintf("TRUNCATE TABLE %s", saver.table))
+	}
 
-		saver.wasEmpty = true // Avoid truncate after inserting the first record
+	if err != nil {
+		log.Error("Truncating the destination table failed")
+		log.Error(err)
+
+		return err
 	}
 
+	saver.truncated = true
+	saver.wasEmpty = true // Avoid truncate after inserting the first record
+
 	return nil
 }
 
+//abortInit forget what has been prepared by a failed initSave, the next Save will prepare everything again.
+func (saver *DbSaver) abortInit(log *logrus.Entry) {
+	saver.colNames = nil
+
+	for _, stmt := range []*sql.Stmt{saver.insertStmt, saver.updateStmt, saver.selectStmt} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+
+	saver.insertStmt, saver.updateStmt, saver.selectStmt = nil, nil, nil
+
+	if saver.tx != nil {
+		_ = saver.restoreChecks(log)
+
+		if err := saver.tx.Rollback(); err != nil {
+			log.Error("Rollbacking transaction failed")
+			log.Error(err)
+		}
+
+		saver.tx = nil
+		// The rollback restored the content of the table
+		saver.truncated = false
+	}
+}
+
 //Save writes the record to the destination.
 func (saver *DbSaver) Save(log *logrus.Entry, record types.Record) error {
 	logDb := log.WithField("datasource", saver.ds.GetName())
@@ -159,7 +272,8 @@ func (saver *DbSaver) Save(log *logrus.Entry, record types.Record) error {
 	if saver.colNames == nil {
 		err = saver.initSave(log, record)
 		if err != nil {
-			return err
+			saver.abortInit(logDb)
+			return fmt.Errorf("preparing %s failed (%v): %w", saver.table, err, common.ErrSaverInit)
 		}
 	}
 
@@ -171,16 +285,46 @@ func (saver *DbSaver) Save(log *logrus.Entry, record types.Record) error {
 		return nil
 	}
 
-	row := make([]interface{}, len(saver.colNames))
+	if saver.savepoint {
+		err = saver.saveRowInSavepoint(logDb, record)
+	} else {
+		err = saver.saveRow(logDb, record)
+	}
 
-	for i, col := range saver.colNames {
-		if record[col] != types.NullValue {
-			row[i] = record[col]
-		} else {
-			row[i] = sql.NullString{}
+	if err != nil {
+		logDb.Error("Saving row failed")
+		logDb.Error(err)
+	}
+
+	return err
+}
+
+//saveRowInSavepoint save the record in a savepoint of the transaction, if it fails only this record is rollbacked.
+func (saver *DbSaver) saveRowInSavepoint(log *logrus.Entry, record types.Record) error {
+	if _, err := saver.tx.Exec("SAVEPOINT kamino_row"); err != nil {
+		return err
+	}
+
+	if err := saver.saveRow(log, record); err != nil {
+		if _, rbErr := saver.tx.Exec("ROLLBACK TO SAVEPOINT kamino_row"); rbErr != nil {
+			log.Error("Rollbacking to savepoint failed")
+			log.Error(rbErr)
 		}
+
+		return err
 	}
 
+	_, err := saver.tx.Exec("RELEASE SAVEPOINT kamino_row")
+
+	return err
+}
+
+//saveRow writes the record with the statement corresponding to the mode.
+func (saver *DbSaver) saveRow(log *logrus.Entry, record types.Record) error {
+	var err error
+
+	row := saver.rowValues(record)
+
 	switch saver.mode {
 	case onlyIfEmpty:
 		_, err = saver.insertStmt.Exec(row...)
@@ -202,6 +346,15 @@ func (saver *DbSaver) Save(log *logrus.Entry, record types.Record) error {
 			_, err = saver.insertStmt.Exec(row...)
 		}
 
+		saver.ids[record[saver.key]] = true
+	case merge:
+		_, ok := saver.ids[record[saver.key]]
+		if ok {
+			err = saver.mergeRow(log, record)
+		} else {
+			_, err = saver.insertStmt.Exec(row...)
+		}
+
 		saver.ids[record[saver.key]] = true
 	case exactCopy:
 		_, ok := saver.ids[record[saver.key]]
@@ -214,24 +367,96 @@ func (saver *DbSaver) Save(log *logrus.Entry, record types.Record) error {
 		saver.ids[record[saver.key]] = true
 	}
 
-	if err != nil {
-		logDb.Error("Saving row failed")
-		logDb.Error(err)
+	return err
+}
+
+//rowValues return the values of the record in the order of the prepared statements.
+func (saver *DbSaver) rowValues(record types.Record) []interface{} {
+	row := make([]interface{}, len(saver.colNames))
+
+	for i, col := range saver.colNames {
+		if record[col] != types.NullValue {
+			row[i] = record[col]
+		} else {
+			row[i] = sql.NullString{}
+		}
+	}
+
+	return row
+}
+
+//mergeRow update the existing line with the record merged following the policy.
+func (saver *DbSaver) mergeRow(log *logrus.Entry, record types.Record) error {
+	if saver.selectStmt != nil {
+		values := make([]sql.NullString, len(saver.colNames))
+		pointers := make([]interface{}, len(saver.colNames))
+
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		err := saver.selectStmt.QueryRowContext(saver.ctx, record[saver.key]).Scan(pointers...)
+		if err != nil {
+			log.Errorf("Reading current row %s=%s failed", saver.key, record[saver.key])
+			return err
+		}
+
+		current := make(types.Record, len(saver.colNames))
+
+		for i, col := range saver.colNames {
+			if values[i].Valid {
+				current[col] = values[i].String
+			} else {
+				current[col] = types.NullValue
+			}
+		}
+
+		record = saver.mergePolicy.Resolve(current, record)
 	}
 
+	_, err := saver.updateStmt.Exec(saver.rowValues(record)...)
+
 	return err
 }
 
+//softDeleteString return the statement marking as deleted a row still alive.
+func (saver *DbSaver) softDeleteString() string {
+	placeholder := make([]string, 0, 1)
+	alive := fmt.Sprintf("%s = %s", saver.softDelete.Column, saver.softDelete.Alive)
+
+	if strings.EqualFold(saver.softDelete.Alive, "NULL") {
+		alive = fmt.Sprintf("%s IS NULL", saver.softDelete.Column)
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s = %s AND %s", saver.table, saver.softDelete.Column, saver.softDelete.Deleted, saver.key, saver.questionMarkByEngine(&placeholder), alive) //nolint: gosec
+}
+
 func (saver *DbSaver) removeNonSynchronized(log *logrus.Entry) error {
 	log.Debug("Deleting non synchronized rows")
 
+	// Without any record saved, the transaction has not been started by the first Save
+	if err := saver.begin(log); err != nil {
+		return err
+	}
+
+	softDelete := ""
+	if saver.softDelete.Column != "" {
+		softDelete = saver.softDeleteString()
+		log.Debug(softDelete)
+	}
+
 	for id, modified := range saver.ids {
 		if !modified {
 			var err error
 
-			if saver.transaction {
+			switch {
+			case softDelete != "" && saver.transaction:
+				_, err = saver.tx.Exec(softDelete, id)
+			case softDelete != "":
+				_, err = saver.db.Exec(softDelete, id)
+			case saver.transaction:
 				_, err = saver.tx.Exec("DELETE from ? WHERE ?=?", saver.table, saver.key, id)
-			} else {
+			default:
 				_, err = saver.db.Exec("DELETE from ? WHERE ?=?", saver.table, saver.key, id)
 			}
 
@@ -254,26 +479,47 @@ func (saver *DbSaver) Close(log *logrus.Entry) error {
 	if saver.mode == exactCopy {
 		err := saver.removeNonSynchronized(logDb)
 		if err != nil {
+			_ = saver.restoreChecks(logDb)
 			return err
 		}
 	}
 
+	if err := saver.restoreChecks(logDb); err != nil {
+		return err
+	}
+
+	var commitErr error
+
 	if saver.transaction && saver.tx != nil {
 		logDb.Debug("Committing transaction")
 
-		err := saver.tx.Commit()
-		if err != nil {
+		commitErr = saver.tx.Commit()
+		if commitErr != nil {
 			logDb.Error("Committing transaction failed")
-			logDb.Error(err)
+			logDb.Error(commitErr)
 		}
 	}
 
+	// Done after the commit since ALTER TABLE commits implicitly the transaction on MySQL
+	var seqErr error
+	if saver.resetSeq {
+		seqErr = saver.resetSequence(logDb)
+	}
+
 	err := saver.ds.CloseDatabase(logDb, false, false)
 	if err != nil {
 		logDb.Error("Close database failed")
 		logDb.Error(err)
 	}
 
+	if commitErr != nil {
+		return commitErr
+	}
+
+	if seqErr != nil {
+		return seqErr
+	}
+
 	return err
 }
 
@@ -283,6 +529,9 @@ func (saver *DbSaver) Reset(log *logrus.Entry) (err error) {
 	saver.colNames = nil
 
 	if saver.transaction && saver.tx != nil {
+		// The rollback does not restore the session settings
+		_ = saver.restoreChecks(logDb)
+
 		logDb.Debug("Rollbacking transaction")
 
 		err = saver.tx.Rollback()
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/common"
+)
+
+//checksByEngine return the statements disabling the foreign keys and triggers checks and the ones restoring them.
+func (saver *DbSaver) checksByEngine() (disable []string, restore []string) {
+	switch saver.engine {
+	case datasource.Mysql:
+		// The setting is done on the session, it must be restored before the connection goes back to the pool
+		if saver.disableFKs {
+			disable = append(disable, "SET FOREIGN_KEY_CHECKS=0")
+			restore = append(restore, "SET FOREIGN_KEY_CHECKS=1")
+		}
+	case datasource.Postgres:
+		// The foreign keys are checked by system triggers, the replica role disables them even if they are not DEFERRABLE
+		// This setting only lasts until the end of the transaction, the commit or rollback restore it
+		if saver.disableFKs || saver.disableTriggers {
+			disable = append(disable, "SET LOCAL session_replication_role = replica")
+		}
+	}
+
+	return disable, restore
+}
+
+//checkOptions verify the checks disabling options are available for the engine.
+func (saver *DbSaver) checkOptions(log *logrus.Entry) error {
+	if saver.disableTriggers && saver.engine == datasource.Mysql {
+		log.Errorf("Triggers can not be disabled on MySQL for %s.%s", saver.database, saver.table)
+		return fmt.Errorf("triggers can not be disabled on MySQL for %s.%s: %w", saver.database, saver.table, common.ErrWrongParameterValue)
+	}
+
+	// The settings are done on the connection of the transaction, without it they could apply to another connection of the pool
+	if (saver.disableFKs || saver.disableTriggers) && !saver.transaction {
+		log.Infof("Disabling the foreign keys or triggers checks needs a transaction, one will be used for %s.%s", saver.database, saver.table)
+		saver.transaction = true
+	}
+
+	return nil
+}
+
+//disableChecks disable the foreign keys and triggers checks for the duration of the transaction.
+func (saver *DbSaver) disableChecks(log *logrus.Entry) error {
+	disable, _ := saver.checksByEngine()
+
+	for _, query := range disable {
+		log.Debug(query)
+
+		if _, err := saver.tx.Exec(query); err != nil {
+			log.Error("Disabling the checks failed")
+			log.Error(err)
+
+			return err
+		}
+	}
+
+	saver.checksDisabled = true
+
+	return nil
+}
+
+//restoreChecks restore the foreign keys and triggers checks disabled at the beginning of the transaction.
+func (saver *DbSaver) restoreChecks(log *logrus.Entry) error {
+	if !saver.checksDisabled {
+		return nil
+	}
+
+	saver.checksDisabled = false
+
+	var firstErr error
+
+	_, restore := saver.checksByEngine()
+
+	for _, query := range restore {
+		log.Debug(query)
+
+		if _, err := saver.tx.Exec(query); err != nil {
+			log.Error("Restoring the checks failed")
+			log.Error(err)
+
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -84,8 +84,13 @@ func (saver *DbSaver) getColNames(log *logrus.Entry, record types.Record) ([]str
 			continue
 		}
 
+		quoted, err := quoteIdentifier(saver.engine, col)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		saver.colNames = append(saver.colNames, col)
-		updateSet = append(updateSet, fmt.Sprintf("%s=%s", col, saver.questionMarkByEngine(&updateSet)))
+		updateSet = append(updateSet, fmt.Sprintf("%s=%s", quoted, saver.questionMarkByEngine(&updateSet)))
 		questionmark = append(questionmark, saver.questionMarkByEngine(&questionmark))
 	}
 
@@ -136,23 +141,58 @@ func (saver *DbSaver) getColNames(log *logrus.Entry, record types.Record) ([]str
 	return questionmark, updateSet, nil
 }
 
+//reviveByEngine return the parts of the insert and update statements setting the soft delete column to its alive value.
+func (saver *DbSaver) reviveByEngine(record types.Record) (string, string, string) {
+	if saver.softDelete.Column == "" || saver.mode != exactCopy {
+		return "", "", ""
+	}
+
+	if _, ok := record[saver.softDelete.Column]; ok {
+		return "", "", ""
+	}
+
+	col, err := quoteIdentifier(saver.engine, saver.softDelete.Column)
+	if err != nil {
+		return "", "", ""
+	}
+
+	return "," + col, "," + saver.softDelete.Alive, fmt.Sprintf(",%s=%s", col, saver.softDelete.Alive)
+}
+
 func (saver *DbSaver) statementsByEngine(log *logrus.Entry, record types.Record) (string, string, error) {
 	var insertString, updateString string
 
+	where := make([]string, 0, 1)
+
 	questionmark, updateSet, err := saver.getColNames(log, record)
 	if err != nil {
 		return "", "", err
 	}
 
-	switch saver.engine {
-	case datasource.Mysql:
-		insertString = fmt.Sprintf("INSERT INTO %s ( `%s`) VALUES ( %s )", saver.table, strings.Join(saver.colNames, "`,`"), strings.Join(questionmark, ","))       //nolint:gosec
-		updateString = fmt.Sprintf("UPDATE %s SET  %s WHERE %s = %s", saver.table, strings.Join(updateSet, ","), saver.key, saver.questionMarkByEngine(&updateSet)) //nolint:gosec
-	case datasource.Postgres:
-		insertString = fmt.Sprintf("INSERT INTO %s ( %s) VALUES ( %s )", saver.table, strings.Join(saver.colNames, ","), strings.Join(questionmark, ","))           //nolint:gosec
-		updateString = fmt.Sprintf("UPDATE %s SET  %s WHERE %s = %s", saver.table, strings.Join(updateSet, ","), saver.key, saver.questionMarkByEngine(&updateSet)) //nolint:gosec
+	// The soft deleted rows present in source are revived, unless the source provides the column
+	reviveCol, reviveValue, reviveSet := saver.reviveByEngine(record)
+
+	// The column names are quoted like on table creation, Postgres would otherwise fold them to lower case
+	columns := make([]string, 0, len(saver.colNames))
+
+	for _, col := range saver.colNames {
+		quoted, err := quoteIdentifier(saver.engine, col)
+		if err != nil {
+			return "", "", err
+		}
+
+		columns = append(columns, quoted)
+	}
+
+	key := ""
+	if saver.key != "" {
+		key = columns[len(columns)-1]
 	}
 
+	insertString = fmt.Sprintf("INSERT INTO %s ( %s%s) VALUES ( %s%s )", saver.table, strings.Join(columns, ","), reviveCol, strings.Join(questionmark, ","), reviveValue) //nolint:gosec
+	updateString = fmt.Sprintf("UPDATE %s SET  %s%s WHERE %s = %s", saver.table, strings.Join(updateSet, ","), reviveSet, key, saver.questionMarkByEngine(&updateSet))     //nolint:gosec
+	saver.selectString = fmt.Sprintf("SELECT %s FROM %s WHERE %s = %s", strings.Join(columns, ","), saver.table, key, saver.questionMarkByEngine(&where))                  //nolint:gosec
+
 	return insertString, updateString, nil
 }
 
@@ -182,7 +222,7 @@ func (saver *DbSaver) createStatement(log *logrus.Entry, record types.Record) er
 		return err
 	}
 
-	if saver.mode == replace || saver.mode == update || saver.mode == exactCopy {
+	if saver.keyed() {
 		log.Debug("Preparing Update statement")
 		//log.Debug(saver.updateString)
 
@@ -201,5 +241,24 @@ func (saver *DbSaver) createStatement(log *logrus.Entry, record types.Record) er
 		}
 	}
 
+	// The current values are only needed if the policy may keep some of them
+	if saver.mode == merge && !saver.mergePolicy.IsNoop() {
+		log.Debug("Preparing Select statement")
+
+		if saver.transaction {
+			saver.selectStmt, err = saver.tx.Prepare(saver.selectString)
+		} else {
+			saver.selectStmt, err = saver.db.Prepare(saver.selectString)
+		}
+
+		if err != nil {
+			log.Error("Preparing Select statement failed")
+			log.Error(saver.selectString)
+			log.Error(err)
+
+			return err
+		}
+	}
+
 	return nil
 }
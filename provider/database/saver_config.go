@@ -21,6 +21,8 @@ func stringToMode(modestr string) dbSaverMode {
 		return exactCopy
 	case "truncate":
 		return truncate
+	case "merge":
+		return merge
 	default:
 		return exactCopy
 	}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+)
+
+//sequenceByEngine return the name of the sequence owned by the key column, "" if there is none.
+func (saver *DbSaver) sequenceByEngine(log *logrus.Entry) (string, error) {
+	var query string
+
+	switch saver.engine {
+	case datasource.Mysql:
+		query = fmt.Sprintf("SELECT column_name FROM information_schema.columns WHERE table_schema = '%s' AND table_name = '%s' AND column_name = '%s' AND extra LIKE '%%auto_increment%%'", saver.database, saver.rawtable, saver.key) //nolint: gosec
+	case datasource.Postgres:
+		query = fmt.Sprintf("SELECT pg_get_serial_sequence('%s', '%s')", saver.table, saver.key) //nolint: gosec
+	}
+
+	log.Debug(query)
+
+	var sequence sql.NullString
+
+	err := saver.db.QueryRowContext(saver.ctx, query).Scan(&sequence)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return sequence.String, nil
+}
+
+//resetSequence set the next value of the sequence (or AUTO_INCREMENT) of the key column to max(key)+1.
+func (saver *DbSaver) resetSequence(log *logrus.Entry) error {
+	log.Debug("Resetting the sequence of the key column")
+
+	sequence, err := saver.sequenceByEngine(log)
+	if err != nil {
+		log.Error("Looking for the sequence of the key column failed")
+		log.Error(err)
+
+		return err
+	}
+
+	if sequence == "" {
+		log.Warnf("Column %s of %s does not have a sequence, nothing to reset", saver.key, saver.table)
+		return nil
+	}
+
+	var next int64
+
+	query := fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) + 1 FROM %s", saver.key, saver.table) //nolint: gosec
+	log.Debug(query)
+
+	err = saver.db.QueryRowContext(saver.ctx, query).Scan(&next)
+	if err != nil {
+		log.Error("Getting the maximum value of the key column failed")
+		log.Error(err)
+
+		return err
+	}
+
+	switch saver.engine {
+	case datasource.Mysql:
+		query = fmt.Sprintf("ALTER TABLE %s AUTO_INCREMENT = %d", saver.table, next) //nolint: gosec
+	case datasource.Postgres:
+		query = fmt.Sprintf("SELECT setval('%s', %d, false)", sequence, next) //nolint: gosec
+	}
+
+	log.Debug(query)
+
+	_, err = saver.db.ExecContext(saver.ctx, query)
+	if err != nil {
+		log.Error("Resetting the sequence failed")
+		log.Error(err)
+	}
+
+	return err
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func (saver *DbSaver) queryTableExistsByEngine(log *logrus.Entry) string {
+	var query string
+
+	switch saver.engine {
+	case datasource.Mysql:
+		query = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = '%s' AND table_name = '%s';", saver.database, saver.rawtable) //nolint: gosec
+	case datasource.Postgres:
+		schema := "public"
+		if saver.schema != "" {
+			schema = saver.schema
+		}
+
+		query = fmt.Sprintf("SELECT COUNT(*) FROM information_schema.tables WHERE table_catalog = '%s' AND table_schema = '%s' AND table_name = '%s';", saver.database, schema, saver.rawtable) //nolint: gosec
+	}
+
+	log.Debug(query)
+
+	return query
+}
+
+//prepareTableCreation determine if the destination table must be created, create it if the column description is known or postpone the creation to the first save.
+//Return true if the table did not exist.
+func (saver *DbSaver) prepareTableCreation(log *logrus.Entry) (bool, error) {
+	var count int
+
+	err := saver.db.QueryRowContext(saver.ctx, saver.queryTableExistsByEngine(log)).Scan(&count)
+	if err != nil {
+		log.Error("Determining if the destination table exists failed")
+		log.Error(err)
+
+		return false, err
+	}
+
+	if count != 0 {
+		log.Debugf("Table %s already exists", saver.table)
+		return false, nil
+	}
+
+	if len(saver.columns) == 0 {
+		log.Debugf("Table %s does not exist, it will be created from the first record", saver.table)
+
+		saver.createTable = true
+
+		return true, nil
+	}
+
+	return true, saver.createDestTable(log, nil)
+}
+
+//quoteIdentifier return the name quoted for the engine.
+func quoteIdentifier(engine datasource.Engine, name string) (string, error) {
+	switch engine {
+	case datasource.Mysql:
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`", nil
+	case datasource.Postgres:
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`, nil
+	}
+
+	return "", fmt.Errorf("can not quote identifiers for %s engine: %w", datasource.EngineToString(engine), common.ErrWrongParameterValue)
+}
+
+//createTableQuery return the CREATE TABLE statement corresponding to the columns description.
+func (saver *DbSaver) createTableQuery(columns []types.Column) (string, error) {
+	definitions := make([]string, 0, len(columns)+1)
+	primaryKeys := make([]string, 0)
+
+	for _, col := range columns {
+		if !col.PrimaryKey && saver.key != "" && strings.EqualFold(col.Name, saver.key) {
+			col.PrimaryKey = true
+		}
+
+		name, err := quoteIdentifier(saver.engine, col.Name)
+		if err != nil {
+			return "", err
+		}
+
+		definition, err := columnDefinition(saver.engine, col)
+		if err != nil {
+			return "", err
+		}
+
+		if col.PrimaryKey {
+			primaryKeys = append(primaryKeys, name)
+		}
+
+		definitions = append(definitions, fmt.Sprintf("%s %s", name, definition))
+	}
+
+	if len(primaryKeys) != 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(primaryKeys, ",")))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s ( %s )", saver.table, strings.Join(definitions, ", ")), nil
+}
+
+//createDestTable create the destination table from the columns description or from the record if the description is not known.
+func (saver *DbSaver) createDestTable(log *logrus.Entry, record types.Record) error {
+	columns := saver.columns
+
+	if len(columns) == 0 {
+		names := make([]string, 0, len(record))
+		for col := range record {
+			names = append(names, col)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			columns = append(columns, types.Column{Name: name, Nullable: true})
+		}
+	}
+
+	query, err := saver.createTableQuery(columns)
+	if err != nil {
+		log.Error("Building the table creation statement failed")
+		log.Error(err)
+
+		return err
+	}
+
+	log.Infof("Creating table %s", saver.table)
+	log.Debug(query)
+
+	_, err = saver.db.ExecContext(saver.ctx, query)
+	if err != nil {
+		log.Error("Creating the destination table failed")
+		log.Error(err)
+
+		return err
+	}
+
+	saver.createTable = false
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestResetSequenceMysqlOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`id`\\) VALUES \\( \\?,\\? \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnResult(sqlmock.NewResult(1, 1))
+	rows = sqlmock.NewRows([]string{"column_name"}).
+		AddRow("id")
+	dmock.ExpectQuery("SELECT column_name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name = 'dtable' AND column_name = 'id' AND extra LIKE '%auto_increment%'").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"next"}).
+		AddRow(43)
+	dmock.ExpectQuery("SELECT COALESCE\\(MAX\\(id\\), 0\\) \\+ 1 FROM dtable").WillReturnRows(rows)
+	dmock.ExpectExec("ALTER TABLE dtable AUTO_INCREMENT = 43").WillReturnResult(sqlmock.NewResult(0, 0))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{ResetSequence: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestResetSequencePostgresOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"id\"\\) VALUES \\( \\$1,\\$2 \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 42", "42").WillReturnResult(sqlmock.NewResult(1, 1))
+	rows = sqlmock.NewRows([]string{"pg_get_serial_sequence"}).
+		AddRow("public.dtable_id_seq")
+	dmock.ExpectQuery("SELECT pg_get_serial_sequence\\('dtable', 'id'\\)").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"next"}).
+		AddRow(43)
+	dmock.ExpectQuery("SELECT COALESCE\\(MAX\\(id\\), 0\\) \\+ 1 FROM dtable").WillReturnRows(rows)
+	dmock.ExpectExec("SELECT setval\\('public.dtable_id_seq', 43, false\\)").WillReturnResult(sqlmock.NewResult(0, 0))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{ResetSequence: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "42", "title": "post 42"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	err = saver.Close(log)
+	if err != nil {
+		t.Errorf("Saver close should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestResetSequenceNoKeyError(t *testing.T) {
+	ddb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	_, err = database.NewSaver(context.Background(), log, &dest, "dtable", "", "insert", types.SaverOptions{ResetSequence: true})
+	if err == nil {
+		t.Errorf("NewSaver should return error")
+	}
+}
@@ -0,0 +1,175 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func TestCreateTableFromSourceOk(t *testing.T) {
+	sdb, smock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "body"}).
+		AddRow(1, "post 1", "hello")
+
+	smock.ExpectQuery("SELECT (.+) from stable").WillReturnRows(rows)
+	rows = sqlmock.NewRows([]string{"column_name"}).
+		AddRow("id")
+	smock.ExpectQuery("SELECT column_name FROM information_schema.key_column_usage WHERE table_schema = 'blog' AND table_name = 'stable' AND constraint_name = 'PRIMARY';").WillReturnRows(rows)
+	source := mockdatasource.MockDatasource{MockedDb: sdb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name = 'dtable';").WillReturnRows(rows)
+	dmock.ExpectExec(`CREATE TABLE dtable \( "id" VARCHAR\(255\) NOT NULL, "title" TEXT, "body" TEXT, PRIMARY KEY \("id"\) \)`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows = sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title").
+		AddRow("body")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"title\",\"body\",\"id\"\\) VALUES \\( \\$1,\\$2,\\$3 \\)")
+	dmock.ExpectPrepare("UPDATE dtable SET  \"title\"=\\$1,\"body\"=\\$2 WHERE \"id\" = \\$3")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	loader, err := database.NewLoader(context.Background(), log, &source, "stable", "", types.LoaderOptions{})
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	columns, err := loader.Columns(log)
+	if err != nil {
+		t.Fatalf("Columns should not return error and returned '%v'", err)
+	}
+
+	if len(columns) != 3 || !columns[0].PrimaryKey || columns[1].PrimaryKey {
+		t.Fatalf("Columns should describe the three columns with id as primary key, returned '%v'", columns)
+	}
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "replace", types.SaverOptions{CreateTable: true, Columns: columns})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		if err = saver.Save(log, record); err != nil {
+			t.Fatalf("Save should not return error and returned '%v'", err)
+		}
+	}
+
+	if err := smock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Loader: %s", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestCreateTableFromRecordOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables WHERE table_schema = 'blog' AND table_name = 'dtable';").WillReturnRows(rows)
+	dmock.ExpectExec("CREATE TABLE dtable \\( `body` LONGTEXT, `id` VARCHAR\\(255\\) NOT NULL, `title` LONGTEXT, PRIMARY KEY \\(`id`\\) \\)").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows = sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("title").
+		AddRow("body")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_schema = 'blog' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( `title`,`body`,`id`\\) VALUES \\( \\?,\\?,\\? \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("post 1", "hello", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Mysql, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{CreateTable: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "1", "title": "post 1", "body": "hello"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
+
+func TestCreateTableMixedCasePostgresOk(t *testing.T) {
+	ddb, dmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM information_schema.tables WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name = 'dtable';").WillReturnRows(rows)
+	dmock.ExpectExec(`CREATE TABLE dtable \( "Name" TEXT, "id" VARCHAR\(255\) NOT NULL, PRIMARY KEY \("id"\) \)`).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows = sqlmock.NewRows([]string{"name"}).
+		AddRow("id").
+		AddRow("Name")
+	dmock.ExpectQuery("SELECT column_name AS name FROM information_schema.columns WHERE table_catalog = 'blog' AND table_schema = 'public' AND table_name ='dtable';").WillReturnRows(rows)
+
+	rows = sqlmock.NewRows([]string{"count"}).
+		AddRow(0)
+	dmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM dtable").WillReturnRows(rows)
+	dmock.ExpectPrepare("INSERT INTO dtable \\( \"Name\",\"id\"\\) VALUES \\( \\$1,\\$2 \\)")
+	dmock.ExpectExec("INSERT INTO dtable").WithArgs("Bob", "1").WillReturnResult(sqlmock.NewResult(1, 1))
+	dest := mockdatasource.MockDatasource{MockedDb: ddb, Type: datasource.Database, Engine: datasource.Postgres, Database: "blog"}
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+	logger.SetLevel(logrus.PanicLevel)
+
+	saver, err := database.NewSaver(context.Background(), log, &dest, "dtable", "id", "insert", types.SaverOptions{CreateTable: true})
+	if err != nil {
+		t.Fatalf("NewSaver should not return error and returned '%v'", err)
+	}
+
+	if err = saver.Save(log, types.Record{"id": "1", "Name": "Bob"}); err != nil {
+		t.Fatalf("Save should not return error and returned '%v'", err)
+	}
+
+	if err := dmock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations on Saver: %s", err)
+	}
+}
@@ -0,0 +1,31 @@
+package generator
+
+// Values used by the faker-style functions.
+var (
+	firstNames = []string{
+		"Aaron", "Abigail", "Adam", "Adrian", "Agnes", "Alexander", "Alice", "Amelia", "Anna", "Arthur",
+		"Ava", "Benjamin", "Camille", "Caroline", "Charles", "Charlotte", "Chloe", "Clara", "Daniel", "David",
+		"Diana", "Edward", "Elena", "Elias", "Emily", "Emma", "Ethan", "Eva", "Felix", "Florence",
+		"Gabriel", "George", "Grace", "Hannah", "Harry", "Henry", "Hugo", "Isaac", "Isabel", "Jack",
+		"James", "Jasmine", "Jules", "Julia", "Laura", "Leo", "Liam", "Lily", "Louis", "Lucas",
+		"Lucy", "Manon", "Marie", "Martin", "Mason", "Mia", "Nathan", "Noah", "Nora", "Olivia",
+		"Oscar", "Paul", "Rose", "Ruby", "Samuel", "Sarah", "Sofia", "Theo", "Thomas", "Victor",
+		"Victoria", "William", "Yasmine", "Zoe",
+	}
+
+	lastNames = []string{
+		"Adams", "Allen", "Baker", "Bernard", "Brown", "Campbell", "Carter", "Clark", "Collins", "Davis",
+		"Dubois", "Durand", "Edwards", "Evans", "Fischer", "Garcia", "Green", "Hall", "Harris", "Hoffmann",
+		"Hughes", "Jackson", "Johnson", "Jones", "King", "Lambert", "Laurent", "Lee", "Lewis", "Lopez",
+		"Martin", "Martinez", "Meyer", "Miller", "Moore", "Moreau", "Morgan", "Muller", "Nelson", "Parker",
+		"Petit", "Roberts", "Robinson", "Rossi", "Roux", "Schmidt", "Scott", "Smith", "Taylor", "Thomas",
+		"Thompson", "Turner", "Walker", "Weber", "White", "Williams", "Wilson", "Wright", "Young",
+	}
+
+	cities = []string{
+		"Ashford", "Bridgeport", "Clayton", "Fairview", "Franklin", "Georgetown", "Greenville", "Kingston", "Lakewood", "Madison",
+		"Marion", "Milford", "Newport", "Oakland", "Riverside", "Salem", "Springfield", "Westfield", "Winchester", "Woodstock",
+	}
+
+	domains = []string{"example.com", "example.org", "example.net"}
+)
@@ -0,0 +1,265 @@
+package generator_test
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/mockdatasource"
+	"github.com/marema31/kamino/provider/generator"
+	"github.com/marema31/kamino/provider/types"
+)
+
+func newPokedex(seed int64) *mockdatasource.MockDatasource {
+	return &mockdatasource.MockDatasource{
+		Name:   "pokedex",
+		Type:   datasource.Synthetic,
+		Engine: datasource.Generator,
+		Generator: datasource.GeneratorConfig{
+			Seed: seed,
+			Tables: map[string]datasource.GeneratorTable{
+				"types": {
+					Count: 3,
+					Columns: map[string]string{
+						"id":   "{{ seq 10 10 }}",
+						"name": `{{ pick "fire" "water" "grass" }}`,
+					},
+				},
+				"pokemon": {
+					Count: 100,
+					Columns: map[string]string{
+						"id":       "{{ seq }}",
+						"name":     "{{ name }}",
+						"email":    "{{ email }}",
+						"hp":       "{{ randInt 1 255 }}",
+						"weight":   "{{ float 0.1 999.9 1 }}",
+						"captured": `{{ date "2020-01-01" "2020-12-31" }}`,
+						"type_id":  `{{ ref "types" "id" }}`,
+						"trainer":  `{{ if chance 0.5 }}{{ null }}{{ else }}{{ firstName }}{{ end }}`,
+						"label":    `{{ col "name" | upper }} #{{ col "id" }}`,
+						"level":    `{{ "41" | int | add1 }}`,
+					},
+				},
+			},
+		},
+	}
+}
+
+func loadAll(t *testing.T, ds datasource.Datasourcer, table string) []types.Record {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	loader, err := generator.NewLoader(context.Background(), log, ds, table, "")
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	records := make([]types.Record, 0)
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			t.Fatalf("Load should not return error and returned '%v'", err)
+		}
+
+		records = append(records, record)
+	}
+
+	if _, err = loader.Load(log); err == nil {
+		t.Errorf("Load should return error after the last record")
+	}
+
+	if err = loader.Close(log); err != nil {
+		t.Errorf("Loader close should not return error and returned '%v'", err)
+	}
+
+	return records
+}
+
+func TestGeneratorOk(t *testing.T) {
+	records := loadAll(t, newPokedex(42), "pokemon")
+
+	if len(records) != 100 {
+		t.Fatalf("The generator should generate 100 records, it generated %d", len(records))
+	}
+
+	emailRe := regexp.MustCompile(`^[a-z]+\.[a-z]+[0-9]+@example\.(com|org|net)$`)
+	dateRe := regexp.MustCompile(`^2020-[0-9]{2}-[0-9]{2}$`)
+	nulls := 0
+
+	for i, record := range records {
+		if record["id"] != strconv.Itoa(i+1) {
+			t.Errorf("The id of the record %d should be %d, it is %s", i, i+1, record["id"])
+		}
+
+		if hp, err := strconv.Atoi(record["hp"]); err != nil || hp < 1 || hp > 255 {
+			t.Errorf("The hp should be between 1 and 255, it is %s", record["hp"])
+		}
+
+		if weight, err := strconv.ParseFloat(record["weight"], 64); err != nil || weight < 0.1 || weight > 999.9 {
+			t.Errorf("The weight should be between 0.1 and 999.9, it is %s", record["weight"])
+		}
+
+		if !emailRe.MatchString(record["email"]) {
+			t.Errorf("The email %s is not valid", record["email"])
+		}
+
+		if !dateRe.MatchString(record["captured"]) {
+			t.Errorf("The date %s is not valid", record["captured"])
+		}
+
+		if record["type_id"] != "10" && record["type_id"] != "20" && record["type_id"] != "30" {
+			t.Errorf("The type_id should reference a type id, it is %s", record["type_id"])
+		}
+
+		if record["level"] != "42" {
+			t.Errorf("The sprig int function should be available, the level is %s", record["level"])
+		}
+
+		if record["trainer"] == types.NullValue {
+			nulls++
+		}
+
+		if want := regexp.MustCompile(`^[A-Z ]+ #` + record["id"] + `$`); !want.MatchString(record["label"]) {
+			t.Errorf("The label %s does not combine the name %s and the id %s", record["label"], record["name"], record["id"])
+		}
+	}
+
+	if nulls == 0 || nulls == len(records) {
+		t.Errorf("The trainer should be NULL for about half of the records, it is for %d", nulls)
+	}
+}
+
+func TestGeneratorReproducible(t *testing.T) {
+	first := loadAll(t, newPokedex(42), "pokemon")
+	second := loadAll(t, newPokedex(42), "pokemon")
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("The generator should generate the same records with the same seed")
+	}
+
+	other := loadAll(t, newPokedex(43), "pokemon")
+
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("The generator should generate other records with another seed")
+	}
+}
+
+func TestGeneratorSingleTable(t *testing.T) {
+	ds := newPokedex(42)
+	delete(ds.Generator.Tables, "pokemon")
+
+	records := loadAll(t, ds, "")
+	if len(records) != 3 || records[2]["id"] != "30" {
+		t.Errorf("The only table should be generated when no table is provided: %v", records)
+	}
+}
+
+func TestGeneratorFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	tests := map[string]struct {
+		table   string
+		where   string
+		columns map[string]string
+	}{
+		"noTable":      {table: ""},
+		"unknownTable": {table: "trainers"},
+		"where":        {table: "pokemon", where: "id > 10"},
+		"syntax":       {table: "pokemon", columns: map[string]string{"id": "{{ seq "}},
+		"unknownFunc":  {table: "pokemon", columns: map[string]string{"id": "{{ uuidv9 }}"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ds := newPokedex(42)
+			if tt.columns != nil {
+				ds.Generator.Tables["pokemon"] = datasource.GeneratorTable{Count: 1, Columns: tt.columns}
+			}
+
+			if _, err := generator.NewLoader(context.Background(), log, ds, tt.table, tt.where); err == nil {
+				t.Errorf("NewLoader should return an error")
+			}
+		})
+	}
+}
+
+func TestGeneratorLoadFail(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	tests := map[string]map[string]string{
+		"randInt":     {"id": "{{ randInt 10 1 }}"},
+		"float":       {"id": "{{ float 10 1 }}"},
+		"pick":        {"id": "{{ pick }}"},
+		"seq":         {"id": "{{ seq 1 2 3 }}"},
+		"date":        {"id": `{{ date "2020-12-31" "2020-01-01" }}`},
+		"dateLayout":  {"id": `{{ date "01/01/2020" "2020-12-31" }}`},
+		"col":         {"id": `{{ col "unknown" }}`},
+		"colLoop":     {"id": `{{ col "name" }}`, "name": `{{ col "id" }}`},
+		"refTable":    {"id": `{{ ref "trainers" "id" }}`},
+		"refColumn":   {"id": `{{ ref "types" "unknown" }}`},
+		"refLoop":     {"id": `{{ ref "pokemon" "id" }}`},
+		"refMutual":   {"id": `{{ ref "types" "id" }}`, "other": `{{ ref "types" "id" }}`},
+		"refOnlyNull": {"id": `{{ ref "types" "name" }}`},
+	}
+
+	for name, columns := range tests {
+		t.Run(name, func(t *testing.T) {
+			ds := newPokedex(42)
+			ds.Generator.Tables["pokemon"] = datasource.GeneratorTable{Count: 1, Columns: columns}
+
+			switch name {
+			case "refMutual":
+				ds.Generator.Tables["types"] = datasource.GeneratorTable{Count: 1, Columns: map[string]string{"id": `{{ ref "pokemon" "id" }}`}}
+			case "refOnlyNull":
+				ds.Generator.Tables["types"] = datasource.GeneratorTable{Count: 1, Columns: map[string]string{"name": "{{ null }}"}}
+			}
+
+			loader, err := generator.NewLoader(context.Background(), log, ds, "pokemon", "")
+			if err != nil {
+				t.Fatalf("NewLoader should not return error and returned '%v'", err)
+			}
+
+			if !loader.Next() {
+				t.Fatalf("Next should return true")
+			}
+
+			if _, err = loader.Load(log); err == nil {
+				t.Errorf("Load should return an error")
+			}
+
+			if loader.Next() {
+				t.Errorf("Next should return false after the failed record")
+			}
+		})
+	}
+}
+
+func TestGeneratorColumns(t *testing.T) {
+	logger := logrus.New()
+	log := logger.WithField("appname", "kamino")
+
+	loader, err := generator.NewLoader(context.Background(), log, newPokedex(42), "types", "")
+	if err != nil {
+		t.Fatalf("NewLoader should not return error and returned '%v'", err)
+	}
+
+	if loader.Name() != "pokedex_types" {
+		t.Errorf("Loader name function does not return the correct name %s", loader.Name())
+	}
+
+	columns, err := loader.Columns(log)
+	if err != nil {
+		t.Fatalf("Columns should not return error and returned '%v'", err)
+	}
+
+	if len(columns) != 2 || columns[0].Name != "id" || columns[1].Name != "name" {
+		t.Errorf("Columns should return id and name, it returned %v", columns)
+	}
+}
@@ -0,0 +1,119 @@
+//Package generator provides a Loader generating synthetic records from the templates of the columns
+package generator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//KaminoGeneratorLoader specifc state for generator Loader provider.
+type KaminoGeneratorLoader struct {
+	ds           datasource.Datasourcer
+	table        *table
+	name         string
+	currentRow   types.Record
+	currentError error
+}
+
+//NewLoader parse the templates of the columns of the table and return a Loader compatible object.
+func NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, tableName string, where string) (*KaminoGeneratorLoader, error) {
+	logGenerator := log.WithField("datasource", ds.GetName())
+
+	gen, ok := ds.(datasource.GeneratorDatasourcer)
+	if !ok {
+		logGenerator.Error("The datasource is not a generator")
+		return nil, fmt.Errorf("%s is not a generator datasource: %w", ds.GetName(), common.ErrWrongParameterValue)
+	}
+
+	config := gen.GetGenerator()
+
+	if tableName == "" && len(config.Tables) == 1 {
+		for name := range config.Tables {
+			tableName = name
+		}
+	}
+
+	if tableName == "" {
+		logGenerator.Error("Generator with several tables needs a table name")
+		return nil, fmt.Errorf("source of sync does not provided a table name: %w", common.ErrMissingParameter)
+	}
+
+	if where != "" {
+		logGenerator.Error("Generator can not filter the records with a where clause")
+		return nil, fmt.Errorf("generator does not support where clause: %w", common.ErrWrongParameterValue)
+	}
+
+	t, err := newTable(config, tableName, nil)
+	if err != nil {
+		logGenerator.Errorf("Unable to parse the columns of %s: %v", tableName, err)
+		return nil, err
+	}
+
+	// The seed is logged to be able to generate the same records again
+	logGenerator.Infof("Generating %d records of %s with seed %d", t.count, tableName, config.Seed)
+
+	return &KaminoGeneratorLoader{ds: ds, table: t, name: ds.GetName() + "_" + tableName}, nil
+}
+
+//Next generates the next record and return false if there is no more records.
+func (gl *KaminoGeneratorLoader) Next() bool {
+	if gl.table.index >= gl.table.count {
+		gl.currentRow = nil
+		return false
+	}
+
+	// To conserve the interface, we can not return the error here but in Load call
+	gl.currentRow, gl.currentError = gl.table.next()
+
+	if gl.currentError != nil {
+		// The record is skipped to avoid generating it again forever
+		gl.table.index++
+	}
+
+	return true
+}
+
+//Load return the record generated by Next.
+func (gl *KaminoGeneratorLoader) Load(log *logrus.Entry) (types.Record, error) {
+	logGenerator := log.WithField("datasource", gl.ds.GetName())
+
+	if gl.currentError != nil {
+		logGenerator.Error("Generating the record failed")
+		logGenerator.Error(gl.currentError)
+
+		return nil, gl.currentError
+	}
+
+	if gl.currentRow == nil {
+		logGenerator.Error("EOF reached")
+		return nil, fmt.Errorf("no more data: %w", common.ErrEOF)
+	}
+
+	return gl.currentRow, nil
+}
+
+//Close does nothing, there is nothing to close.
+func (gl *KaminoGeneratorLoader) Close(log *logrus.Entry) error {
+	return nil
+}
+
+//Name give the name of the source.
+func (gl *KaminoGeneratorLoader) Name() string {
+	return gl.name
+}
+
+//Columns returns the description of the generated columns, their types are unknown.
+func (gl *KaminoGeneratorLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	columns := make([]types.Column, 0, len(gl.table.columns))
+
+	for _, col := range gl.table.columns {
+		columns = append(columns, types.Column{Name: col, Nullable: true})
+	}
+
+	return columns, nil
+}
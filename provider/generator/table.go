@@ -0,0 +1,284 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Layout of the dates if not provided.
+const defaultDateLayout = "2006-01-02"
+
+// Number of decimals of the floats if not provided.
+const defaultFloatDecimals = 2
+
+//table generates the records of a table of a generator datasource.
+type table struct {
+	name     string
+	count    int
+	config   datasource.GeneratorConfig
+	rng      *rand.Rand
+	columns  []string
+	tmpls    map[string]*template.Template
+	index    int                 // Index of the record being generated
+	current  types.Record        // Values already generated for the current record
+	pending  map[string]bool     // Columns being generated, to detect the loops between columns
+	refs     map[string][]string // Values of the columns of the other tables, indexed by table.column
+	building []string            // Tables waiting for the values of this one, to detect the loops between tables
+}
+
+//newTable parse the templates of the columns of the table and return its generator.
+func newTable(config datasource.GeneratorConfig, name string, building []string) (*table, error) {
+	cfg, ok := config.Tables[name]
+	if !ok {
+		return nil, fmt.Errorf("the generator has no table %s: %w", name, common.ErrWrongParameterValue)
+	}
+
+	// Each table has its own random generator, the records of a table do not depend on the order of the generations
+	h := fnv.New64a()
+	h.Write([]byte(name)) //nolint: errcheck
+
+	t := &table{
+		name:     name,
+		count:    cfg.Count,
+		config:   config,
+		rng:      rand.New(rand.NewSource(config.Seed ^ int64(h.Sum64()))), //nolint: gosec
+		columns:  make([]string, 0, len(cfg.Columns)),
+		tmpls:    make(map[string]*template.Template),
+		refs:     make(map[string][]string),
+		building: building,
+	}
+
+	funcs := sprig.TxtFuncMap()
+	for fname, f := range t.funcMap() {
+		funcs[fname] = f
+	}
+
+	for col, spec := range cfg.Columns {
+		tmpl, err := template.New(col).Funcs(funcs).Parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("parsing the column %s of %s: %w", col, name, err)
+		}
+
+		t.tmpls[col] = tmpl
+		t.columns = append(t.columns, col)
+	}
+
+	// The columns are always generated in the same order to be reproducible
+	sort.Strings(t.columns)
+
+	return t, nil
+}
+
+//funcMap return the functions available in the templates of the columns.
+func (t *table) funcMap() template.FuncMap {
+	return template.FuncMap{
+		"seq":       t.seq,
+		"randInt":   t.randInt,
+		"float":     t.randFloat,
+		"pick":      t.pick,
+		"chance":    t.chance,
+		"null":      func() string { return types.NullValue },
+		"firstName": func() string { return firstNames[t.rng.Intn(len(firstNames))] },
+		"lastName":  func() string { return lastNames[t.rng.Intn(len(lastNames))] },
+		"name":      t.fullName,
+		"email":     t.email,
+		"city":      func() string { return cities[t.rng.Intn(len(cities))] },
+		"date":      t.date,
+		"ref":       t.ref,
+		"col":       t.value,
+	}
+}
+
+//seq return the position of the record in a sequence starting at 1 (or at the first argument) with an increment of 1 (or the second argument).
+func (t *table) seq(args ...int64) (int64, error) {
+	start, step := int64(1), int64(1)
+
+	switch len(args) {
+	case 0:
+	case 1:
+		start = args[0]
+	case 2:
+		start, step = args[0], args[1]
+	default:
+		return 0, fmt.Errorf("seq accepts at most a start and a step: %w", common.ErrWrongParameterValue)
+	}
+
+	return start + step*int64(t.index), nil
+}
+
+//randInt return a random integer between min and max included.
+func (t *table) randInt(min, max int64) (int64, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt maximum %d lower than minimum %d: %w", max, min, common.ErrWrongParameterValue)
+	}
+
+	return min + t.rng.Int63n(max-min+1), nil
+}
+
+//randFloat return a random number between min and max with two decimals (or the number of decimals provided).
+func (t *table) randFloat(min, max float64, decimals ...int) (string, error) {
+	if max < min {
+		return "", fmt.Errorf("float maximum %g lower than minimum %g: %w", max, min, common.ErrWrongParameterValue)
+	}
+
+	precision := defaultFloatDecimals
+	if len(decimals) > 0 {
+		precision = decimals[0]
+	}
+
+	return strconv.FormatFloat(min+t.rng.Float64()*(max-min), 'f', precision, 64), nil
+}
+
+//pick return one of the values.
+func (t *table) pick(values ...string) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("pick needs at least one value: %w", common.ErrMissingParameter)
+	}
+
+	return values[t.rng.Intn(len(values))], nil
+}
+
+//chance return true with the provided probability (between 0 and 1).
+func (t *table) chance(probability float64) bool {
+	return t.rng.Float64() < probability
+}
+
+//fullName return a first name followed by a last name.
+func (t *table) fullName() string {
+	return firstNames[t.rng.Intn(len(firstNames))] + " " + lastNames[t.rng.Intn(len(lastNames))]
+}
+
+//email return an email address built from a first name and a last name.
+func (t *table) email() string {
+	first := strings.ToLower(firstNames[t.rng.Intn(len(firstNames))])
+	last := strings.ToLower(lastNames[t.rng.Intn(len(lastNames))])
+
+	return fmt.Sprintf("%s.%s%d@%s", first, last, t.rng.Intn(1000), domains[t.rng.Intn(len(domains))])
+}
+
+//date return a random date between from and to included, the dates are in the layout provided (2006-01-02 by default).
+func (t *table) date(from string, to string, layout ...string) (string, error) {
+	l := defaultDateLayout
+	if len(layout) > 0 {
+		l = layout[0]
+	}
+
+	start, err := time.Parse(l, from)
+	if err != nil {
+		return "", err
+	}
+
+	end, err := time.Parse(l, to)
+	if err != nil {
+		return "", err
+	}
+
+	if end.Before(start) {
+		return "", fmt.Errorf("date %s before %s: %w", to, from, common.ErrWrongParameterValue)
+	}
+
+	seconds := int64(end.Sub(start) / time.Second)
+
+	return start.Add(time.Duration(t.rng.Int63n(seconds+1)) * time.Second).Format(l), nil
+}
+
+//ref return one of the values of the column of another table of the generator, typically one of its ids.
+func (t *table) ref(name string, column string) (string, error) {
+	key := name + "." + column
+
+	values, ok := t.refs[key]
+	if !ok {
+		chain := append(append(make([]string, 0, len(t.building)+1), t.building...), t.name)
+
+		for _, b := range chain {
+			if b == name {
+				return "", fmt.Errorf("the tables %s and %s reference each other: %w", t.name, name, common.ErrWrongParameterValue)
+			}
+		}
+
+		other, err := newTable(t.config, name, chain)
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := other.tmpls[column]; !ok {
+			return "", fmt.Errorf("the table %s has no column %s: %w", name, column, common.ErrWrongParameterValue)
+		}
+
+		values = make([]string, 0, other.count)
+
+		for other.index < other.count {
+			record, err := other.next()
+			if err != nil {
+				return "", err
+			}
+
+			if record[column] != types.NullValue {
+				values = append(values, record[column])
+			}
+		}
+
+		t.refs[key] = values
+	}
+
+	if len(values) == 0 {
+		return "", fmt.Errorf("no value of %s to reference: %w", key, common.ErrWrongParameterValue)
+	}
+
+	return values[t.rng.Intn(len(values))], nil
+}
+
+//value return the value of the column for the current record, generating it if it is not already done.
+func (t *table) value(column string) (string, error) {
+	if v, ok := t.current[column]; ok {
+		return v, nil
+	}
+
+	tmpl, ok := t.tmpls[column]
+	if !ok {
+		return "", fmt.Errorf("the table %s has no column %s: %w", t.name, column, common.ErrWrongParameterValue)
+	}
+
+	if t.pending[column] {
+		return "", fmt.Errorf("the column %s of %s depends on itself: %w", column, t.name, common.ErrWrongParameterValue)
+	}
+
+	t.pending[column] = true
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", err
+	}
+
+	t.current[column] = strings.TrimSpace(buf.String())
+
+	return t.current[column], nil
+}
+
+//next generate the next record of the table.
+func (t *table) next() (types.Record, error) {
+	t.current = make(types.Record, len(t.columns))
+	t.pending = make(map[string]bool)
+
+	for _, col := range t.columns {
+		if _, err := t.value(col); err != nil {
+			return nil, err
+		}
+	}
+
+	t.index++
+
+	return t.current, nil
+}
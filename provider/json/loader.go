@@ -84,3 +84,8 @@ func (jl *KaminoJSONLoader) Close(log *logrus.Entry) error {
 func (jl *KaminoJSONLoader) Name() string {
 	return jl.name
 }
+
+//Columns returns the description of the columns, the file does not describe them before the first record.
+func (jl *KaminoJSONLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	return nil, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/marema31/kamino/provider/common"
 	"github.com/marema31/kamino/provider/csv"
 	"github.com/marema31/kamino/provider/database"
+	"github.com/marema31/kamino/provider/generator"
 	"github.com/marema31/kamino/provider/json"
 	"github.com/marema31/kamino/provider/types"
 	"github.com/marema31/kamino/provider/yaml"
@@ -20,22 +21,58 @@ type Loader interface {
 	Load(*logrus.Entry) (types.Record, error)
 	Close(*logrus.Entry) error
 	Name() string
+	Columns(*logrus.Entry) ([]types.Column, error)
+}
+
+//Lookup provides way to read the record having a key value.
+type Lookup interface {
+	Fetch(*logrus.Entry, string) (types.Record, error)
+	Close(*logrus.Entry) error
 }
 
 //NewLoader analyze the datasource and return object implementing Loader of the asked type.
-func (p *KaminoProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (Loader, error) {
+func (p *KaminoProvider) NewLoader(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string, options types.LoaderOptions) (Loader, error) {
 	engine := ds.GetEngine()
 
 	switch engine {
 	case datasource.Mysql, datasource.Postgres:
-		return database.NewLoader(ctx, log, ds, table, where)
+		return database.NewLoader(ctx, log, ds, table, where, options)
 	case datasource.CSV:
 		return csv.NewLoader(ctx, log, ds)
 	case datasource.JSON:
 		return json.NewLoader(ctx, log, ds)
 	case datasource.YAML:
 		return yaml.NewLoader(ctx, log, ds)
+	case datasource.Generator:
+		return generator.NewLoader(ctx, log, ds, table, where)
 	default:
 		return nil, fmt.Errorf("don't know how to manage this datasource engine: %w", common.ErrWrongParameterValue)
 	}
 }
+
+//ForeignKeys returns the foreign keys between the tables of a database datasource.
+func (p *KaminoProvider) ForeignKeys(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer) ([]types.ForeignKey, error) {
+	if ds.GetType() != datasource.Database {
+		return nil, fmt.Errorf("foreign keys only exist on database datasource: %w", common.ErrWrongParameterValue)
+	}
+
+	return database.ForeignKeys(ctx, log, ds)
+}
+
+//Count returns the number of records of a database table.
+func (p *KaminoProvider) Count(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, where string) (int64, error) {
+	if ds.GetType() != datasource.Database {
+		return 0, fmt.Errorf("records can only be counted on database datasource: %w", common.ErrWrongParameterValue)
+	}
+
+	return database.Count(ctx, log, ds, table, where)
+}
+
+//NewLookup returns an object reading the records of a database table by the value of their key.
+func (p *KaminoProvider) NewLookup(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string) (Lookup, error) {
+	if ds.GetType() != datasource.Database {
+		return nil, fmt.Errorf("records can only be looked up by key on database datasource: %w", common.ErrWrongParameterValue)
+	}
+
+	return database.NewLookup(ctx, log, ds, table, key)
+}
@@ -5,12 +5,16 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/types"
 )
 
 //Provider provides Loader and Saver objects adapted to the datasource.
 type Provider interface {
-	NewLoader(context.Context, *logrus.Entry, datasource.Datasourcer, string, string) (Loader, error)
-	NewSaver(context.Context, *logrus.Entry, datasource.Datasourcer, string, string, string) (Saver, error)
+	NewLoader(context.Context, *logrus.Entry, datasource.Datasourcer, string, string, types.LoaderOptions) (Loader, error)
+	NewSaver(context.Context, *logrus.Entry, datasource.Datasourcer, string, string, string, types.SaverOptions) (Saver, error)
+	ForeignKeys(context.Context, *logrus.Entry, datasource.Datasourcer) ([]types.ForeignKey, error)
+	Count(context.Context, *logrus.Entry, datasource.Datasourcer, string, string) (int64, error)
+	NewLookup(context.Context, *logrus.Entry, datasource.Datasourcer, string, string) (Lookup, error)
 }
 
 //KaminoProvider implement the Provider interface with action on database and files.
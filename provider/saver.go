@@ -22,13 +22,18 @@ type Saver interface {
 	Name() string
 }
 
+//Truncater is implemented by the savers able to empty the destination before the first record is saved.
+type Truncater interface {
+	Truncate(*logrus.Entry) error
+}
+
 //NewSaver analyze the datasource and return object implementing Saver of the asked type.
-func (p *KaminoProvider) NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string) (Saver, error) {
+func (p *KaminoProvider) NewSaver(ctx context.Context, log *logrus.Entry, ds datasource.Datasourcer, table string, key string, mode string, options types.SaverOptions) (Saver, error) {
 	engine := ds.GetEngine()
 
 	switch engine {
 	case datasource.Mysql, datasource.Postgres:
-		return database.NewSaver(ctx, log, ds, table, key, mode)
+		return database.NewSaver(ctx, log, ds, table, key, mode, options)
 	case datasource.CSV:
 		return csv.NewSaver(ctx, log, ds)
 	case datasource.JSON:
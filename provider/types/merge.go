@@ -0,0 +1,101 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Policies available for the merge mode.
+const (
+	MergeSource      = "source"      // The values of the source overwrite the destination
+	MergeDestination = "destination" // The values of the destination are kept
+	MergeNewest      = "newest"      // The values of the side with the most recent timestamp column win
+)
+
+//MergePolicy describes how the rows existing on both sides are merged.
+type MergePolicy struct {
+	Policy    string            // Policy applied to all columns (source by default)
+	Timestamp string            // Column compared by the newest policy
+	Columns   map[string]string // Policy by column (lowercase name), overriding the global one
+}
+
+// Layouts tried to compare the timestamp columns, if none matches the values are compared as string.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+//sourceIsNewer return true if the timestamp of the source record is more recent than the destination one.
+func (mp MergePolicy) sourceIsNewer(current Record, record Record) bool {
+	src, dst := record[mp.Timestamp], current[mp.Timestamp]
+
+	switch {
+	case src == NullValue || src == "":
+		return false
+	case dst == NullValue || dst == "":
+		return true
+	}
+
+	srcTime, srcOk := parseTimestamp(src)
+	dstTime, dstOk := parseTimestamp(dst)
+
+	if srcOk && dstOk {
+		return srcTime.After(dstTime)
+	}
+
+	return src > dst
+}
+
+//IsNoop return true if the policy always keep the source values (as replace mode).
+func (mp MergePolicy) IsNoop() bool {
+	if mp.Policy != "" && mp.Policy != MergeSource {
+		return false
+	}
+
+	for _, policy := range mp.Columns {
+		if policy != MergeSource {
+			return false
+		}
+	}
+
+	return true
+}
+
+//Resolve return the record to be written in destination for a row existing on both sides.
+func (mp MergePolicy) Resolve(current Record, record Record) Record {
+	newer := mp.Timestamp != "" && mp.sourceIsNewer(current, record)
+	merged := make(Record, len(record))
+
+	for col, value := range record {
+		policy, ok := mp.Columns[strings.ToLower(col)]
+		if !ok {
+			policy = mp.Policy
+		}
+
+		_, exists := current[col]
+
+		switch {
+		case policy == MergeDestination && exists:
+			merged[col] = current[col]
+		case policy == MergeNewest && !newer && exists:
+			merged[col] = current[col]
+		default:
+			merged[col] = value
+		}
+	}
+
+	return merged
+}
@@ -1,7 +1,56 @@
 package types
 
+import "time"
+
 //Record is the exchange type between Loader and Saver.
 type Record map[string]string
 
 //NullValue flag string for NULL value in database columns.
 const NullValue string = "NULL&NULL@NIL"
+
+//Column describes a column of the dataset provided by a Loader.
+type Column struct {
+	Name       string
+	Type       string // Engine independent type (see provider/database), empty if unknown
+	Nullable   bool
+	Length     int64 // 0 if unknown
+	Precision  int64 // 0 if unknown
+	Scale      int64
+	PrimaryKey bool
+}
+
+//SaverOptions contains the optional behaviors of a Saver.
+type SaverOptions struct {
+	CreateTable        bool        // Create the destination table if it does not exist
+	Columns            []Column    // Description of the columns used for the table creation
+	Merge              MergePolicy // Conflict resolution of the merge mode
+	SoftDelete         SoftDelete  // Mark the rows instead of deleting them in exactCopy mode
+	ResetSequence      bool        // Reset the sequence of the key column to max(key)+1 on close
+	DisableForeignKeys bool        // Disable the foreign keys checks during the transaction
+	DisableTriggers    bool        // Disable the triggers during the transaction
+	TolerateErrors     bool        // A record failing to be saved must not abort the transaction of the other records
+}
+
+//SoftDelete describes the column set on the rows missing from the source instead of deleting them.
+type SoftDelete struct {
+	Column  string // Column marking the deleted rows, empty to really delete them
+	Deleted string // SQL expression set on the rows missing from the source
+	Alive   string // SQL expression set on the rows present in the source
+}
+
+//LoaderOptions contains the optional behaviors of a Loader.
+type LoaderOptions struct {
+	OrderBy    string        // Column used to sort the records (only for database)
+	After      string        // Only the records with OrderBy column greater than this value (only for database)
+	ChunkSize  int           // Read the records by chunks of this size paginated on OrderBy column or primary key, 0 for one query (only for database)
+	ChunkPause time.Duration // Pause between two chunks (only for database)
+}
+
+//ForeignKey describes a foreign key between two tables of a database.
+type ForeignKey struct {
+	Name       string
+	Table      string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
@@ -87,3 +87,8 @@ func (yl *KaminoYAMLLoader) Close(log *logrus.Entry) error {
 func (yl *KaminoYAMLLoader) Name() string {
 	return yl.name
 }
+
+//Columns returns the description of the columns, the file does not describe them before the first record.
+func (yl *KaminoYAMLLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	return nil, nil
+}
@@ -0,0 +1,191 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Actions on the cache asked by the cache commands.
+const (
+	cacheList    = "list"
+	cacheRefresh = "refresh"
+	cacheClear   = "clear"
+)
+
+// cacheMeta describes the content of a cache file, it is saved aside the cache file.
+type cacheMeta struct {
+	Key     string    `json:"key"`
+	Source  string    `json:"source"`
+	Table   string    `json:"table"`
+	Where   string    `json:"where"`
+	Rows    int       `json:"rows"`
+	Created time.Time `json:"created"`
+}
+
+//cacheKey return the hash identifying the data written in the cache: the source, the table, the where clause and the filters.
+func cacheKey(source parsedSourceConfig, filters interface{}) string {
+	// fmt prints the maps sorted by keys, the result is stable
+	definition := fmt.Sprintf("%s\x1e%s\x1e%s\x1e%v", source.name(), source.table, source.where, filters)
+	if source.origin != "" {
+		definition += "\x1e" + source.origin
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(definition)))
+}
+
+func (st *Step) cacheFile() string {
+	return st.cacheCfg.ds.FillTmplValues().FilePath
+}
+
+func (st *Step) cacheMetaFile() string {
+	return st.cacheFile() + ".meta"
+}
+
+//readCacheMeta return the description of the current cache file, nil if it does not exist.
+func (st *Step) readCacheMeta() *cacheMeta {
+	content, err := ioutil.ReadFile(st.cacheMetaFile())
+	if err != nil {
+		return nil
+	}
+
+	var meta cacheMeta
+	if err = json.Unmarshal(content, &meta); err != nil {
+		return nil
+	}
+
+	return &meta
+}
+
+//cacheMatches return true if the cache file has been created with the current definition of the source.
+func (st *Step) cacheMatches() bool {
+	meta := st.readCacheMeta()
+
+	return meta != nil && meta.Key == st.cacheKey
+}
+
+//writeCacheMeta saves the description of the cache file that has just been created.
+func (st *Step) writeCacheMeta(log *logrus.Entry) {
+	meta := cacheMeta{
+		Key:     st.cacheKey,
+		Source:  st.sourceCfg.name(),
+		Table:   st.sourceCfg.table,
+		Where:   st.sourceCfg.where,
+		Rows:    st.count,
+		Created: time.Now(),
+	}
+
+	content, err := json.Marshal(meta)
+	if err == nil {
+		err = ioutil.WriteFile(st.cacheMetaFile(), content, 0644) //nolint: gosec
+	}
+
+	if err != nil {
+		log.Warnf("Writing the description of the cache failed: %v", err)
+	}
+}
+
+//cacheStatus return the state of the cache file and its age.
+func (st *Step) cacheStatus() (string, string) {
+	stat, err := st.cacheCfg.ds.Stat()
+	if err != nil {
+		return "missing", ""
+	}
+
+	age := time.Since(stat.ModTime())
+
+	switch {
+	case !st.cacheMatches():
+		return "stale definition", age.Round(time.Second).String()
+	case age > st.cacheTTL:
+		return "expired", age.Round(time.Second).String()
+	default:
+		return "valid", age.Round(time.Second).String()
+	}
+}
+
+//printCache shows the cache file with its age, TTL, row count and source.
+func (st *Step) printCache() {
+	status, age := st.cacheStatus()
+	rows := ""
+	source := fmt.Sprintf("%s(%s)", st.sourceCfg.name(), st.sourceCfg.table)
+
+	if meta := st.readCacheMeta(); meta != nil {
+		rows = strconv.Itoa(meta.Rows)
+		source = fmt.Sprintf("%s(%s)", meta.Source, meta.Table)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Cache", "Status", "Age", "TTL", "Rows", "Source"})
+	table.Append([]string{st.cacheFile(), status, age, st.cacheTTL.String(), rows, source})
+
+	fmt.Printf("\n  ---------- %s ----------\n", st.Name)
+	table.Render()
+	fmt.Println()
+}
+
+//clearCache removes the cache file and its description.
+func (st *Step) clearCache(log *logrus.Entry) error {
+	for _, file := range []string{st.cacheFile(), st.cacheMetaFile()} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Removing %s failed", file)
+			log.Error(err)
+
+			return err
+		}
+	}
+
+	log.Infof("Cache %s removed", st.cacheFile())
+
+	return nil
+}
+
+//doCacheAction manages the cache without synchronizing the destinations.
+func (st *Step) doCacheAction(ctx context.Context, log *logrus.Entry) error {
+	if st.cacheCfg.ds == nil {
+		log.Debug("No cache for this step")
+		return nil
+	}
+
+	switch st.cacheAction {
+	case cacheList:
+		st.printCache()
+	case cacheClear:
+		if st.dryRun {
+			log.Infof("Dry run: the cache %s would be removed", st.cacheFile())
+			return nil
+		}
+
+		return st.clearCache(log)
+	case cacheRefresh:
+		if st.dryRun {
+			log.Infof("Dry run: the cache %s would be refreshed from %s", st.cacheFile(), st.source.Name())
+			return nil
+		}
+
+		// Without destinations, the source is only copied to the cache
+		saver, err := st.prov.NewSaver(ctx, log, st.cacheCfg.ds, st.cacheCfg.table, "", "", types.SaverOptions{})
+		if err != nil {
+			return err
+		}
+
+		st.cacheSaver = saver
+
+		if err = st.copyData(ctx, log); err != nil {
+			return err
+		}
+
+		log.Infof("Cache %s refreshed. %d rows", st.cacheFile(), st.count)
+	}
+
+	return nil
+}
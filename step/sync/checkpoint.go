@@ -0,0 +1,199 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider/types"
+)
+
+const defaultCheckpointInterval = 1000
+
+// checkpointState is the content of the checkpoint file.
+type checkpointState struct {
+	Key          string            `json:"key"`
+	Destinations map[string]string `json:"destinations"`
+}
+
+// checkpointConfig contains the parameters and the progress of a resumable synchronization.
+type checkpointConfig struct {
+	key      string
+	file     string
+	interval int
+	resumed  bool
+	previous map[string]string
+	ids      []string
+	after    []string // Last key written by the previous run for each destination
+	last     []string // Last key written by this run for each destination
+	durable  []bool   // Is the write on this destination kept on failure
+}
+
+func destID(dest parsedDestConfig) string {
+	return fmt.Sprintf("%s(%s)", dest.ds.GetName(), dest.table)
+}
+
+//compareKeys compare two key values numerically if possible, as string otherwise.
+func compareKeys(a string, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	default:
+		return 0
+	}
+}
+
+//load reads the checkpoint file of a previous run if it exists and return the options to be used for reading the source.
+func (cp *checkpointConfig) load(log *logrus.Entry, dests []parsedDestConfig) (types.LoaderOptions, error) {
+	options := types.LoaderOptions{OrderBy: cp.key}
+
+	content, err := ioutil.ReadFile(cp.file)
+	if os.IsNotExist(err) {
+		return options, nil
+	}
+
+	if err != nil {
+		log.Errorf("Reading checkpoint file %s failed", cp.file)
+		log.Error(err)
+
+		return options, err
+	}
+
+	var state checkpointState
+
+	if err = json.Unmarshal(content, &state); err != nil {
+		log.Errorf("Parsing checkpoint file %s failed", cp.file)
+		log.Error(err)
+
+		return options, err
+	}
+
+	if state.Key != cp.key {
+		log.Errorf("Checkpoint file %s was created for key %s, not %s", cp.file, state.Key, cp.key)
+		return options, fmt.Errorf("checkpoint file %s was created for another key: %w", cp.file, errDatasource)
+	}
+
+	cp.resumed = true
+	cp.previous = state.Destinations
+
+	// The source must be read from the destination with the less progress
+	for i, dest := range dests {
+		after := state.Destinations[destID(dest)]
+		if after == "" {
+			options.After = ""
+			break
+		}
+
+		if i == 0 || compareKeys(after, options.After) < 0 {
+			options.After = after
+		}
+	}
+
+	log.Infof("Resuming synchronization from checkpoint %s (%s > '%s')", cp.file, cp.key, options.After)
+
+	return options, nil
+}
+
+//addDestination register the destination and adapt its mode if the previous run already wrote some rows to it.
+func (cp *checkpointConfig) addDestination(log *logrus.Entry, dest parsedDestConfig) parsedDestConfig {
+	id := destID(dest)
+	after := cp.previous[id]
+
+	cp.ids = append(cp.ids, id)
+	cp.after = append(cp.after, after)
+	cp.last = append(cp.last, after)
+	// Transaction are rollbacked and files are written at the end, their progress is lost on failure
+	durable := dest.ds.GetType() == datasource.Database && !dest.ds.IsTransaction()
+	cp.durable = append(cp.durable, durable)
+
+	// Only the destinations that kept rows of the previous run must be completed instead of rewritten
+	if durable && after != "" {
+		switch dest.mode {
+		case "truncate", "onlyifempty":
+			// The table already contains the rows of the previous run
+			dest.mode = "insert"
+		case "replace", "update", "insert":
+		default:
+			log.Warnf("Resuming %s: the rows not present in source will not be deleted", id)
+
+			dest.mode = "replace"
+		}
+	}
+
+	return dest
+}
+
+//skip return true if the record has already been written to the destination by the previous run.
+func (cp *checkpointConfig) skip(index int, record types.Record) bool {
+	return cp.after[index] != "" && compareKeys(record[cp.key], cp.after[index]) <= 0
+}
+
+//saved register the record as written to the destination.
+func (cp *checkpointConfig) saved(index int, record types.Record) {
+	if cp.durable[index] {
+		cp.last[index] = record[cp.key]
+	}
+}
+
+//write saves the progress to the checkpoint file.
+func (cp *checkpointConfig) write(log *logrus.Entry) error {
+	state := checkpointState{Key: cp.key, Destinations: make(map[string]string)}
+
+	for id, after := range cp.previous {
+		state.Destinations[id] = after
+	}
+
+	for i, id := range cp.ids {
+		state.Destinations[id] = cp.last[i]
+	}
+
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(cp.file), 0755); err != nil {
+		log.Errorf("Creating folder for checkpoint file %s failed", cp.file)
+		log.Error(err)
+
+		return err
+	}
+
+	// Write then rename to never have a partially written checkpoint file
+	tmpFile := cp.file + ".tmp"
+
+	if err = ioutil.WriteFile(tmpFile, content, 0644); err != nil { //nolint: gosec
+		log.Errorf("Writing checkpoint file %s failed", cp.file)
+		log.Error(err)
+
+		return err
+	}
+
+	log.Debugf("Checkpoint saved in %s", cp.file)
+
+	return os.Rename(tmpFile, cp.file)
+}
+
+//remove deletes the checkpoint file since the synchronization is complete.
+func (cp *checkpointConfig) remove(log *logrus.Entry) {
+	err := os.Remove(cp.file)
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("Removing checkpoint file %s failed", cp.file)
+		log.Error(err)
+	}
+}
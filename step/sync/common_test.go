@@ -3,6 +3,8 @@ package sync_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/marema31/kamino/mockprovider"
 
@@ -15,14 +17,24 @@ import (
 
 func setupDo(path string, filename string) (context.Context, *logrus.Entry, datasource.Datasourcers, *viper.Viper, *mockprovider.MockProvider) {
 	dss := mockdatasource.New()
+	cacheFile := filepath.Join(os.TempDir(), "kamino_sync_cache.json")
 	ds1 := mockdatasource.MockDatasource{Name: "ds1", Database: "db1", User: "user1", Tags: []string{"tag1", "tag2"}}
 	ds2 := mockdatasource.MockDatasource{Name: "ds2", Database: "db2", User: "user2", Tags: []string{"tag2"}}
 	ds3 := mockdatasource.MockDatasource{Name: "ds3", Database: "db3", User: "user3", Tags: []string{"tag3"}}
 	ds4 := mockdatasource.MockDatasource{Name: "ds4", Database: "db4", User: "user4", Tags: []string{"tag3"}}
 	ds5 := mockdatasource.MockDatasource{Name: "ds5", Database: "db4", User: "user4", Tags: []string{"tagerror"}, ErrorOpenDb: fmt.Errorf("fake error")}
-	dscache := mockdatasource.MockDatasource{Name: "dscache", FilePath: "db4", Tags: []string{"tagcache"}}
-	dscachenotexist := mockdatasource.MockDatasource{Name: "dscachenotexist", FilePath: "db4", Tags: []string{"tagcachenotexist"}, FileNotExists: true}
-	dserrorfile := mockdatasource.MockDatasource{Name: "dserror", FilePath: "db4", Tags: []string{"tagcache"}, ErrorOpenFile: fmt.Errorf("fake error")}
+	dscache := mockdatasource.MockDatasource{Name: "dscache", Type: datasource.File, FilePath: cacheFile, Tags: []string{"tagcache"}}
+	dscachenotexist := mockdatasource.MockDatasource{Name: "dscachenotexist", FilePath: cacheFile, Tags: []string{"tagcachenotexist"}, FileNotExists: true}
+	dserrorfile := mockdatasource.MockDatasource{Name: "dserror", FilePath: cacheFile, Tags: []string{"tagcache"}, ErrorOpenFile: fmt.Errorf("fake error")}
+	dseusource := mockdatasource.MockDatasource{Name: "dseusource", Database: "db5", Tags: []string{"tagpairsource", "zone:europe"}}
+	dsassource := mockdatasource.MockDatasource{Name: "dsassource", Database: "db6", Tags: []string{"tagpairsource", "zone:asia"}}
+	dseudest1 := mockdatasource.MockDatasource{Name: "dseudest1", Database: "db7", Tags: []string{"tagpairdest", "zone:europe"}}
+	dseudest2 := mockdatasource.MockDatasource{Name: "dseudest2", Database: "db8", Tags: []string{"tagpairdest", "zone:europe"}}
+	dsasdest := mockdatasource.MockDatasource{Name: "dsasdest", Database: "db9", Tags: []string{"tagpairdest", "zone:asia"}}
+	dslookupfile := mockdatasource.MockDatasource{Name: "dslookupfile", Type: datasource.File, Tags: []string{"taglookupfile"}}
+	dslookupdb := mockdatasource.MockDatasource{Name: "dslookupdb", Database: "db10", Engine: datasource.Mysql, Tags: []string{"taglookupdb"}}
+	dsmixeddb := mockdatasource.MockDatasource{Name: "dsmixeddb", Database: "db11", Type: datasource.Database, Tags: []string{"tagmixed"}}
+	dsmixedfile := mockdatasource.MockDatasource{Name: "dsmixedfile", Type: datasource.File, Tags: []string{"tagmixed"}}
 
 	dss.Insert(true, []string{"tag1", "tag2"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&ds2})
 	dss.Insert(true, []string{"tag3"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&ds3, &ds4})
@@ -31,6 +43,11 @@ func setupDo(path string, filename string) (context.Context, *logrus.Entry, data
 	dss.Insert(true, []string{"tagcachenotexist"}, []datasource.Type{datasource.File}, []datasource.Engine{datasource.JSON}, []*mockdatasource.MockDatasource{&dscachenotexist})
 	dss.Insert(true, []string{"tagerror"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&ds5})
 	dss.Insert(true, []string{"tagerrorfile"}, []datasource.Type{datasource.File}, []datasource.Engine{datasource.JSON}, []*mockdatasource.MockDatasource{&dserrorfile})
+	dss.Insert(true, []string{"tagpairsource"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&dseusource, &dsassource})
+	dss.Insert(true, []string{"tagpairdest"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&dseudest1, &dsasdest, &dseudest2})
+	dss.Insert(true, []string{"taglookupfile"}, []datasource.Type{datasource.File}, []datasource.Engine{datasource.CSV}, []*mockdatasource.MockDatasource{&dslookupfile})
+	dss.Insert(true, []string{"taglookupdb"}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&dslookupdb})
+	dss.Insert(true, []string{"tagmixed"}, nil, nil, []*mockdatasource.MockDatasource{&dsmixeddb, &dsmixedfile})
 	dss.Insert(true, []string{""}, []datasource.Type{datasource.Database}, []datasource.Engine{datasource.Mysql}, []*mockdatasource.MockDatasource{&ds2})
 	v := viper.New()
 	v.SetConfigName(filename)
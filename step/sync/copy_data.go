@@ -4,11 +4,64 @@ import (
 	"context"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/progress"
 	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
+	"github.com/marema31/kamino/throttle"
 )
 
+//limiters return the throughput limiters of the step and of the datasources used, each limiter only once.
+func (st *Step) limiters(sources ...datasource.Datasourcer) []*throttle.Limiter {
+	limiters := make([]*throttle.Limiter, 0)
+	seen := make(map[*throttle.Limiter]bool)
+
+	add := func(ls ...*throttle.Limiter) {
+		for _, l := range ls {
+			if l != nil && !seen[l] {
+				seen[l] = true
+				limiters = append(limiters, l)
+			}
+		}
+	}
+
+	add(st.limiter)
+
+	for _, source := range sources {
+		add(source.GetLimiters()...)
+	}
+
+	for _, dest := range st.destsUsed {
+		add(dest.ds.GetLimiters()...)
+	}
+
+	return limiters
+}
+
+//recordSize return the approximative number of bytes transferred for the record.
+func recordSize(record types.Record) int {
+	size := 0
+	for col, value := range record {
+		size += len(col) + len(value)
+	}
+
+	return size
+}
+
+//valuesSize return the number of bytes of the values of the record, to be compared to the size of a source file.
+func valuesSize(record types.Record) int {
+	size := 0
+	for _, value := range record {
+		size += len(value)
+	}
+
+	return size
+}
+
 func (st *Step) copyData(ctx context.Context, log *logrus.Entry) error {
 	source := st.source
+	sourceCfg := st.sourceCfg
+	fromCache := st.fromCache
 	destinations := make([]provider.Saver, len(st.destinations))
 	copy(destinations, st.destinations)
 
@@ -16,16 +69,27 @@ func (st *Step) copyData(ctx context.Context, log *logrus.Entry) error {
 		destinations = append(destinations, st.cacheSaver)
 	} else if st.cacheLoader != nil {
 		source = st.cacheLoader
+		fromCache = true
+		sourceCfg = parsedSourceConfig{ds: st.cacheCfg.ds, table: st.cacheCfg.table}
 	}
 
+	limiters := st.limiters(sourceCfg.datasources()...)
+
 	log.Infof("Will synchronize %s to", source.Name())
 
 	for _, d := range destinations {
 		log.Infof("   - %s", d.Name())
 	}
 
-	if st.dryRun {
-		return nil
+	if st.verify != nil {
+		// The filtered records are checksummed when saved, the verification is not impacted by the filters or the rejects
+		st.verify.saved = make([]checksum, len(destinations))
+	}
+
+	tracker := st.newTracker(ctx, log, sourceCfg)
+	if tracker != nil {
+		progress.Register(tracker)
+		defer progress.Unregister(tracker)
 	}
 
 	for source.Next() {
@@ -37,26 +101,55 @@ func (st *Step) copyData(ctx context.Context, log *logrus.Entry) error {
 			return err
 		}
 
-		for _, f := range st.filters {
-			if record, err = f.Filter(record); err != nil {
-				log.Error("Filtering failed:")
-				log.Error(err)
+		for _, l := range limiters {
+			if err = l.Wait(ctx, 1, recordSize(record)); err != nil {
+				log.Debug("Synchronization cancelled")
+				return nil
+			}
+		}
 
+		// The cache contains the records already filtered
+		records := []types.Record{record}
+		if !fromCache {
+			records, err = st.applyFilters(ctx, log, record)
+			if err != nil {
 				return err
 			}
 		}
 
-		for _, d := range destinations {
-			if err = d.Save(log, record); err != nil {
-				log.Error("Destination writing failed:")
-				log.Error(err)
-
-				return err
+		for _, record := range records {
+			for i, d := range destinations {
+				if st.checkpoint != nil && st.checkpoint.skip(i, record) {
+					continue
+				}
+
+				if err = d.Save(log, record); err != nil {
+					if err = st.reject(ctx, log, d, record, err); err != nil {
+						return err
+					}
+				} else if st.verify != nil {
+					st.verify.saved[i].add(record, st.verify.columns)
+				}
+
+				if st.checkpoint != nil {
+					st.checkpoint.saved(i, record)
+				}
 			}
 		}
 		st.count++
 
-		if st.count%1000 == 0 {
+		if st.checkpoint != nil && st.count%st.checkpoint.interval == 0 {
+			if err = st.checkpoint.write(log); err != nil {
+				log.Warnf("Saving checkpoint failed: %v", err)
+			}
+		}
+
+		if tracker != nil {
+			// The live display already shows the progression
+			if tracker.Add(valuesSize(record)) && !progress.Active() {
+				log.Info(tracker.String())
+			}
+		} else if st.count%1000 == 0 {
 			log.Infof("%d rows treated", st.count)
 		}
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
 )
 
 //Finish manage the finish of the step (called after all other step of the same priority has ended their Do).
@@ -22,12 +23,31 @@ func (st *Step) Finish(log *logrus.Entry) {
 	}
 
 	if st.cacheSaver != nil {
-		st.cacheSaver.Close(logStep)
+		if err := st.cacheSaver.Close(logStep); err != nil {
+			logStep.Error(err)
+		} else {
+			st.writeCacheMeta(logStep)
+		}
 	}
 
+	st.closeRejects(logStep)
+	st.closeLookups()
+
+	closed := true
+
 	for _, d := range st.destinations {
 		if err := d.Close(logStep); err != nil {
 			logStep.Error(err)
+
+			closed = false
+		}
+	}
+
+	if st.checkpoint != nil {
+		if closed {
+			st.checkpoint.remove(logStep)
+		} else if err := st.checkpoint.write(logStep); err != nil {
+			logStep.Error(err)
 		}
 	}
 }
@@ -51,11 +71,20 @@ func (st *Step) Cancel(log *logrus.Entry) {
 		}
 	}
 
+	st.closeRejects(logStep)
+	st.closeLookups()
+
 	for _, d := range st.destinations {
 		if err := d.Reset(logStep); err != nil {
 			logStep.Error(err)
 		}
 	}
+
+	if st.checkpoint != nil {
+		if err := st.checkpoint.write(logStep); err != nil {
+			logStep.Error(err)
+		}
+	}
 }
 
 func (st *Step) useCache(ctx context.Context, logStep *logrus.Entry) (bool, error) {
@@ -66,12 +95,17 @@ func (st *Step) useCache(ctx context.Context, logStep *logrus.Entry) (bool, erro
 		ttlExpired = time.Since(cacheStat.ModTime()) > st.cacheTTL
 	}
 
-	if os.IsNotExist(errFile) || ttlExpired {
+	stale := errFile == nil && !st.cacheMatches()
+	if stale {
+		logStep.Info("Cache file created with another definition of the source, where clause or filters")
+	}
+
+	if os.IsNotExist(errFile) || ttlExpired || stale {
 		logStep.Info("Cache file does not exist or too old, recreating it")
 
 		var err error
 
-		st.cacheSaver, err = st.prov.NewSaver(ctx, logStep, st.cacheCfg.ds, st.cacheCfg.table, "", "")
+		st.cacheSaver, err = st.prov.NewSaver(ctx, logStep, st.cacheCfg.ds, st.cacheCfg.table, "", "", types.SaverOptions{})
 		if err != nil {
 			return false, err
 		}
@@ -99,9 +133,13 @@ func (st *Step) useCache(ctx context.Context, logStep *logrus.Entry) (bool, erro
 		return false, err
 	}
 
+	if stale {
+		logStep.Warn("Using a cache created with another definition of the source, where clause or filters")
+	}
+
 	logStep.Info("Using cache as source")
 
-	cacheLoader, err := st.prov.NewLoader(ctx, logStep, st.cacheCfg.ds, st.cacheCfg.table, "")
+	cacheLoader, err := st.prov.NewLoader(ctx, logStep, st.cacheCfg.ds, st.cacheCfg.table, "", types.LoaderOptions{})
 	if err != nil {
 		logStep.Error("Opening cache file failed .. skipping it")
 		logStep.Error(err)
@@ -117,16 +155,28 @@ func (st *Step) Do(ctx context.Context, log *logrus.Entry) error {
 	logStep := log.WithField("name", st.Name).WithField("datasource", st.sourceCfg.ds.GetName()).WithField("type", "sync")
 	logStep.Debug("Beginning step")
 
-	if len(st.destinations) == 0 {
+	if st.cacheAction != "" {
+		return st.doCacheAction(ctx, logStep)
+	}
+
+	if len(st.destsUsed) == 0 {
 		logStep.Info("All destinations has been skipped")
 		return nil
 	}
 
+	if st.subset != nil {
+		return st.doSubset(ctx, logStep)
+	}
+
+	if st.dryRun {
+		return st.plan(ctx, logStep)
+	}
+
 	if st.cacheCfg.ds != nil {
 		done, err := st.useCache(ctx, logStep)
 		if done {
 			logStep.Infof("Synchronization from cache ok. %d rows", st.count)
-			return nil
+			return st.verifyAfterSync(ctx, logStep)
 		}
 
 		if err != nil && st.ignoreErrors {
@@ -158,7 +208,7 @@ func (st *Step) Do(ctx context.Context, log *logrus.Entry) error {
 		logStep.Infof("Synchronization ok. %d rows", st.count)
 	}
 
-	return nil
+	return st.verifyAfterSync(ctx, logStep)
 }
 
 // ToSkip return true if the step must be skipped.
@@ -3,8 +3,15 @@ package sync_test
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
 	"github.com/marema31/kamino/step/sync"
 )
 
@@ -195,6 +202,42 @@ func TestDoForceCacheOk(t *testing.T) {
 	steps[0].Finish(log)
 }
 
+func TestDoForceCacheDecryptOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "decryptcache")
+
+	// The cache contains the records already decrypted
+	prov.Contents = map[string][]map[string]string{
+		"dscache": {
+			{"id": "1", "ssn": "123-45-6789"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "decryptcache", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	superseed := make(map[string]string)
+	superseed["sync.forceCacheOnly"] = "true"
+	steps[0].PostLoad(log, superseed)
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+
+	if len(prov.Savers[0].Content) != 1 || prov.Savers[0].Content[0]["ssn"] != "123-45-6789" {
+		t.Errorf("The records of the cache should be saved without being decrypted again, saved: %v", prov.Savers[0].Content)
+	}
+}
+
 func TestDoForceCacheError(t *testing.T) {
 	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "syncok")
 
@@ -365,3 +408,1177 @@ func TestDoDryRunOk(t *testing.T) {
 		t.Errorf("Do should not return error, returned: %v", err)
 	}
 }
+
+func TestDoCreateTableOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "createtable")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "createtable", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	if !prov.Savers[0].Options.CreateTable {
+		t.Errorf("The first destination should be created if it does not exist")
+	}
+
+	if prov.Savers[1].Options.CreateTable {
+		t.Errorf("The second destination should not be created")
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoDryRunPlanOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "nocache")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "1", "name": "myname", "hp": "100"},
+			{"id": "3", "name": "Charlie", "hp": "50"},
+		},
+		"ds3": {
+			{"id": "4", "name": "Dave", "hp": "20"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "nocache", 0, v, dss, prov, false, true, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	if len(prov.Savers) != 0 {
+		t.Fatalf("Init should not create savers in dry run, created: %d", len(prov.Savers))
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	expected := [][]int{
+		{1, 0, 0, 2, 0}, // replace: id 2 inserted, id 1 identical, id 3 not in source
+		{0, 0, 0, 1, 2}, // onlyIfEmpty on a non empty table
+		{2, 0, 0, 0, 0}, // onlyIfEmpty on an empty table
+	}
+
+	for i, e := range expected {
+		inserted, updated, deleted, unchanged, skipped, err := sync.PlanCounts(steps[0], i)
+		if err != nil {
+			t.Fatalf("PlanCounts should not return error, returned: %v", err)
+		}
+
+		if inserted != e[0] || updated != e[1] || deleted != e[2] || unchanged != e[3] || skipped != e[4] {
+			t.Errorf("Plan of destination %d should be %v, it was: [%d %d %d %d %d]", i, e, inserted, updated, deleted, unchanged, skipped)
+		}
+	}
+}
+
+func TestDoDryRunMergeOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "merge")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "1", "name": "Alice", "hp": "50"},
+			{"id": "3", "name": "Charlie", "hp": "50"},
+		},
+		"ds3": {
+			{"id": "1", "name": "Old", "hp": "100", "updated": "2020-01-01 10:00:00"},
+			{"id": "2", "name": "Robert", "hp": "20", "updated": "2020-06-01T00:00:00Z"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "merge", 0, v, dss, prov, false, true, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice", "hp": "100", "updated": "2021-01-01 00:00:00"},
+		{"id": "2", "name": "Bob", "hp": "20", "updated": "2019-01-01 00:00:00"},
+	})
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	expected := [][]int{
+		{1, 0, 0, 2, 0}, // hp kept from destination: id 1 unchanged, id 2 inserted
+		{0, 1, 0, 1, 0}, // newest: id 1 more recent in source, id 2 more recent in destination
+		{2, 0, 0, 0, 0}, // empty table
+	}
+
+	for i, e := range expected {
+		inserted, updated, deleted, unchanged, skipped, err := sync.PlanCounts(steps[0], i)
+		if err != nil {
+			t.Fatalf("PlanCounts should not return error, returned: %v", err)
+		}
+
+		if inserted != e[0] || updated != e[1] || deleted != e[2] || unchanged != e[3] || skipped != e[4] {
+			t.Errorf("Plan of destination %d should be %v, it was: [%d %d %d %d %d]", i, e, inserted, updated, deleted, unchanged, skipped)
+		}
+	}
+}
+
+func TestDoVerifyOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "verify")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "2", "name": "Bob", "hp": "10"},
+			{"id": "1", "name": "Alice", "hp": "10"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "verify", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice", "hp": "20"},
+		{"id": "2", "name": "Bob", "hp": "20"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoVerifySoftDeleteOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "verifysoftdelete")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "2", "name": "Bob"},
+			{"id": "1", "name": "Alice"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "verifysoftdelete", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	if prov.Wheres[len(prov.Wheres)-1] != "deleted_at IS NULL" {
+		t.Errorf("Only the rows alive should be verified, the destination was read with '%s'", prov.Wheres[len(prov.Wheres)-1])
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoVerifyFilteredOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "verifywhere")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "2", "name": "Bob", "hp": "20"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "verifywhere", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice", "hp": "5"},
+		{"id": "2", "name": "Bob", "hp": "20"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	// The destination is committed by Do to be verified, Finish must not close it again
+	if prov.Savers[0].Closed != 1 {
+		t.Errorf("The destination should be closed once by Do, it was closed %d times", prov.Savers[0].Closed)
+	}
+
+	steps[0].Finish(log)
+
+	if prov.Savers[0].Closed != 1 {
+		t.Errorf("The destination should not be closed again by Finish, it was closed %d times", prov.Savers[0].Closed)
+	}
+}
+
+func TestDoVerifyRejectedOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "verify")
+
+	v.Set("maxErrors", 1)
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "verify", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+	})
+	sync.MockDestinationError(steps[0])
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoVerifyError(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "verify")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds2": {
+			{"id": "1", "name": "Alice"},
+			{"id": "2", "name": "Robert"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "verify", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err == nil {
+		t.Errorf("Do should return error")
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoCheckpointResumeOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "checkpoint")
+
+	file := filepath.Join(t.TempDir(), "checkpoint.json")
+	v.Set("checkpoint.file", file)
+
+	if err := ioutil.WriteFile(file, []byte(`{"key":"id","destinations":{"ds2(tabledest1)":"1"}}`), 0644); err != nil {
+		t.Fatalf("Writing checkpoint file should not returns an error, returned: %v", err)
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "checkpoint", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+		{"id": "10", "name": "Carol"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	if len(content) != 2 || content[0]["id"] != "2" || content[1]["id"] != "10" {
+		t.Errorf("Only the records after the checkpoint should be saved, saved: %v", content)
+	}
+
+	mode, err := sync.DestinationMode(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationMode should not return error, returned: %v", err)
+	}
+
+	if mode != "replace" {
+		t.Errorf("The exactCopy mode should be replaced by replace when resuming, it is %s", mode)
+	}
+
+	steps[0].Finish(log)
+
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("The checkpoint file should be removed after success")
+	}
+}
+
+func TestDoCheckpointResumeNotWritten(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "checkpoint")
+
+	file := filepath.Join(t.TempDir(), "checkpoint.json")
+	v.Set("checkpoint.file", file)
+
+	if err := ioutil.WriteFile(file, []byte(`{"key":"id","destinations":{"ds2(tabledest1)":""}}`), 0644); err != nil {
+		t.Fatalf("Writing checkpoint file should not returns an error, returned: %v", err)
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "checkpoint", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	mode, err := sync.DestinationMode(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationMode should not return error, returned: %v", err)
+	}
+
+	if mode != "exactcopy" {
+		t.Errorf("The mode of a destination without rows from the previous run should be kept, it is %s", mode)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoCheckpointCancel(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "checkpoint")
+
+	file := filepath.Join(t.TempDir(), "checkpoint.json")
+	v.Set("checkpoint.file", file)
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "checkpoint", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+	})
+	sync.MockDestinationError(steps[0])
+
+	err = steps[0].Do(context.Background(), log)
+	if err == nil {
+		t.Errorf("Do should return error")
+	}
+
+	steps[0].Cancel(log)
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("The checkpoint file should be written on cancel, reading it returned: %v", err)
+	}
+
+	if string(content) != `{"key":"id","destinations":{"ds2(tabledest1)":""}}` {
+		t.Errorf("The checkpoint file content is wrong: %s", content)
+	}
+}
+
+func TestDoThrottleOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "throttle")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "throttle", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	content := make([]map[string]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		content = append(content, map[string]string{"id": fmt.Sprintf("%d", i), "name": "Alice"})
+	}
+
+	sync.MockSourceContent(steps[0], content)
+
+	start := time.Now()
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 rows at 50 rows/s should last at least 100ms, lasted %v", elapsed)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoSubsetOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "subset")
+
+	prov.MockFKs = []types.ForeignKey{
+		{Name: "fk_lines", Table: "order_lines", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+		{Name: "fk_customer", Table: "orders", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}},
+		{Name: "fk_audit", Table: "audit", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+	}
+	prov.Contents = map[string][]map[string]string{
+		"ds1(orders)": {
+			{"id": "1", "customer_id": "10"},
+		},
+		"ds1(order_lines)": {
+			{"id": "100", "order_id": "1"},
+			{"id": "101", "order_id": "1"},
+		},
+		"ds1(customers)": {
+			{"id": "10", "name": "O'Neil"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "subset", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	expectedWheres := []string{"RAND() < 0.01", "order_id IN ('1')", "id IN ('10')", "id IN ('1')"}
+	if fmt.Sprint(prov.Wheres) != fmt.Sprint(expectedWheres) {
+		t.Errorf("The queries should be %v, they were %v", expectedWheres, prov.Wheres)
+	}
+
+	tables, err := sync.SaversTables(steps[0])
+	if err != nil {
+		t.Fatalf("SaversTables should not return error, returned: %v", err)
+	}
+
+	// The savers are created from the children to the parents
+	expectedTables := []string{"order_lines:2", "orders:1", "customers:1"}
+	if fmt.Sprint(tables) != fmt.Sprint(expectedTables) {
+		t.Errorf("The tables written should be %v, they were %v", expectedTables, tables)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoSubsetTruncate(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "subsettruncate")
+
+	prov.MockFKs = []types.ForeignKey{
+		{Name: "fk_lines", Table: "order_lines", Columns: []string{"order_id"}, RefTable: "orders", RefColumns: []string{"id"}},
+		{Name: "fk_customer", Table: "orders", Columns: []string{"customer_id"}, RefTable: "customers", RefColumns: []string{"id"}},
+	}
+	prov.Contents = map[string][]map[string]string{
+		"ds1(orders)": {
+			{"id": "1", "customer_id": "10"},
+		},
+		"ds1(order_lines)": {
+			{"id": "100", "order_id": "1"},
+		},
+		"ds1(customers)": {
+			{"id": "10", "name": "O'Neil"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "subsettruncate", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	expectedTruncated := []string{"order_lines", "orders", "customers"}
+	if fmt.Sprint(prov.Truncated) != fmt.Sprint(expectedTruncated) {
+		t.Errorf("The tables should be truncated in the order %v, they were %v", expectedTruncated, prov.Truncated)
+	}
+
+	for _, saver := range prov.Savers {
+		if !saver.Options.DisableForeignKeys {
+			t.Errorf("The foreign keys checks should be disabled for %s", saver.Table)
+		}
+
+		if (saver.Table == "orders") != (saver.Key == "id") {
+			t.Errorf("The key of the destination should only be used for the root table, %s uses '%s'", saver.Table, saver.Key)
+		}
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestDoRejectsOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "rejects")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "rejects", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+	})
+	sync.MockDestinationError(steps[0])
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+
+	rejects := prov.Savers[len(prov.Savers)-1]
+	if len(rejects.Content) != 1 || rejects.Content[0]["id"] != "1" || rejects.Content[0]["_error"] != "fake error" {
+		t.Errorf("The failing record should be written in rejects, rejects contains: %v", rejects.Content)
+	}
+}
+
+func TestDoRejectsTooManyErrors(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "rejects")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "rejects", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+	sync.MockDestinationError(steps[0])
+
+	err = steps[0].Do(context.Background(), log)
+	if err == nil {
+		t.Errorf("Do should return error")
+	}
+
+	steps[0].Cancel(log)
+
+	rejects := prov.Savers[len(prov.Savers)-1]
+	if len(rejects.Content) != 2 {
+		t.Errorf("The failing records should be written in rejects, rejects contains: %v", rejects.Content)
+	}
+}
+
+func TestDoRejectsInitError(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "rejects")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "rejects", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+	})
+	sync.MockDestinationSaveError(steps[0], fmt.Errorf("fake error: %w", common.ErrSaverInit))
+
+	err = steps[0].Do(context.Background(), log)
+	if err == nil {
+		t.Errorf("Do should return error even if maxErrors is not reached")
+	}
+
+	steps[0].Cancel(log)
+
+	rejects := prov.Savers[len(prov.Savers)-1]
+	if len(rejects.Content) != 0 {
+		t.Errorf("The record should not be rejected when the destination can not be prepared, rejects contains: %v", rejects.Content)
+	}
+}
+
+func TestDoValidateRejectsOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "validate")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "validate", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "email": "alice@example.com"},
+		{"id": "2", "email": "bob"},
+		{"id": "1", "email": "charlie@example.com"},
+		{"id": "3", "email": types.NullValue},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	if len(content) != 2 || content[0]["id"] != "1" || content[1]["id"] != "3" {
+		t.Errorf("Only the valid records should be saved, destination contains: %v", content)
+	}
+
+	rejects := prov.Savers[len(prov.Savers)-1]
+	if len(rejects.Content) != 2 || rejects.Content[0]["id"] != "2" || rejects.Content[1]["email"] != "charlie@example.com" {
+		t.Fatalf("The invalid records should be written in rejects, rejects contains: %v", rejects.Content)
+	}
+
+	if rejects.Content[0]["_destination"] != "validate" || !strings.Contains(rejects.Content[0]["_error"], "email: regex") || !strings.Contains(rejects.Content[1]["_error"], "id: unique") {
+		t.Errorf("The rejected records should contain the violated rule, rejects contains: %v", rejects.Content)
+	}
+}
+
+func TestDoProgressOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "progress")
+
+	prov.MockCount = 2
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "progress", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+}
+
+func TestProgressTotal(t *testing.T) {
+	tests := map[string]string{
+		"progressunion": "progressunion:0: 0/4 rows",
+		"progressmixed": "progressmixed:0: 0 rows,",
+	}
+
+	for filename, expected := range tests {
+		t.Run(filename, func(t *testing.T) {
+			ctx, log, dss, v, prov := setupDo("testdata/good/steps/", filename)
+
+			prov.MockCount = 2
+
+			_, steps, err := sync.Load(ctx, log, "testdata/good", filename, 0, v, dss, prov, false, false, nil)
+			if err != nil {
+				t.Fatalf("Load should not returns an error, returned: %v", err)
+			}
+
+			description, err := sync.TrackerDescription(ctx, log, steps[0])
+			if err != nil {
+				t.Fatalf("TrackerDescription should not returns an error, returned: %v", err)
+			}
+
+			if !strings.HasPrefix(description, expected) {
+				t.Errorf("The progression should start with %q, it is %q", expected, description)
+			}
+		})
+	}
+}
+
+func TestDoCacheActions(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "syncok")
+	cacheFile := filepath.Join(os.TempDir(), "kamino_sync_cache.json")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "syncok", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	if err = steps[0].PostLoad(log, map[string]string{"sync.cacheAction": "refresh"}); err != nil {
+		t.Fatalf("PostLoad should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	sync.MockSourceContent(steps[0], []map[string]string{
+		{"id": "1", "name": "Alice"},
+		{"id": "2", "name": "Bob"},
+	})
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	steps[0].Finish(log)
+
+	if len(prov.Savers) != 1 || len(prov.Savers[0].Content) != 2 {
+		t.Errorf("Only the cache should be written")
+	}
+
+	content, err := ioutil.ReadFile(cacheFile + ".meta")
+	if err != nil {
+		t.Fatalf("The cache description should be written, reading it returned: %v", err)
+	}
+
+	if !strings.Contains(string(content), `"source":"ds1","table":"tablesource","where":"","rows":2`) {
+		t.Errorf("The cache description is wrong: %s", content)
+	}
+
+	if err = steps[0].PostLoad(log, map[string]string{"sync.cacheAction": "list"}); err != nil {
+		t.Fatalf("PostLoad should not returns an error, returned: %v", err)
+	}
+
+	if err = steps[0].Do(context.Background(), log); err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	if err = steps[0].PostLoad(log, map[string]string{"sync.cacheAction": "clear"}); err != nil {
+		t.Fatalf("PostLoad should not returns an error, returned: %v", err)
+	}
+
+	if err = steps[0].Do(context.Background(), log); err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	if _, err := os.Stat(cacheFile + ".meta"); !os.IsNotExist(err) {
+		t.Errorf("The cache description should be removed")
+	}
+
+	if err = steps[0].PostLoad(log, map[string]string{"sync.cacheAction": "unknown"}); err == nil {
+		t.Errorf("PostLoad should returns an error for an unknown action")
+	}
+}
+
+func TestDoCacheActionsDryRun(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "syncok")
+	cacheFile := filepath.Join(os.TempDir(), "kamino_sync_cache.json")
+
+	if err := ioutil.WriteFile(cacheFile, []byte("[]"), 0644); err != nil {
+		t.Fatalf("Writing cache file should not returns an error, returned: %v", err)
+	}
+	defer os.Remove(cacheFile)
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "syncok", 0, v, dss, prov, false, true, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	for _, action := range []string{"refresh", "clear"} {
+		if err = steps[0].PostLoad(log, map[string]string{"sync.cacheAction": action}); err != nil {
+			t.Fatalf("PostLoad should not returns an error, returned: %v", err)
+		}
+
+		if err = steps[0].Init(ctx, log); err != nil {
+			t.Fatalf("Init should not returns an error, returned: %v", err)
+		}
+
+		if err = steps[0].Do(context.Background(), log); err != nil {
+			t.Errorf("Do should not return error, returned: %v", err)
+		}
+
+		steps[0].Finish(log)
+	}
+
+	if len(prov.Savers) != 0 {
+		t.Errorf("The cache should not be refreshed in dry-run mode")
+	}
+
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Errorf("The cache should not be removed in dry-run mode")
+	}
+}
+
+func TestDoUnionOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "union")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds3": {
+			{"id": "1", "name": "Alice"},
+		},
+		"ds4": {
+			{"id": "2", "name": "Bob"},
+			{"id": "3", "name": "Charlie"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "union", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	expected := []string{"ds3", "ds4", "ds4"}
+	if len(content) != len(expected) {
+		t.Fatalf("The destination should contain the records of both sources, contains: %v", content)
+	}
+
+	for i, origin := range expected {
+		if content[i]["shard"] != origin {
+			t.Errorf("The record %d should come from %s, it was: %v", i, origin, content[i])
+		}
+	}
+}
+
+func TestLoadPairLimiters(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "pairthrottle")
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "pairthrottle", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	if len(steps) != 2 {
+		t.Fatalf("Load should return a step by source, returned: %d", len(steps))
+	}
+
+	first, err := sync.Limiter(steps[0])
+	if err != nil {
+		t.Fatalf("Limiter should not returns an error, returned: %v", err)
+	}
+
+	second, err := sync.Limiter(steps[1])
+	if err != nil {
+		t.Fatalf("Limiter should not returns an error, returned: %v", err)
+	}
+
+	if first == nil || first == second {
+		t.Errorf("Each paired step should have its own limiter")
+	}
+}
+
+func TestDoPairOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "pair")
+
+	prov.Contents = map[string][]map[string]string{
+		"dseusource": {
+			{"id": "1", "name": "Alice"},
+			{"id": "2", "name": "Bob"},
+		},
+		"dsassource": {
+			{"id": "3", "name": "Charlie"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "pair", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	if len(steps) != 2 {
+		t.Fatalf("Load should return a step by source, returned: %d", len(steps))
+	}
+
+	// Europe has two destinations and Asia one
+	expected := [][]int{{2, 2}, {1}}
+
+	for i, step := range steps {
+		err = step.Init(ctx, log)
+		if err != nil {
+			t.Fatalf("Init should not returns an error, returned: %v", err)
+		}
+
+		err = step.Do(context.Background(), log)
+		if err != nil {
+			t.Errorf("Do should not return error, returned: %v", err)
+		}
+
+		for j, count := range expected[i] {
+			content, err := sync.DestinationContent(step, j)
+			if err != nil {
+				t.Fatalf("DestinationContent should not return error, returned: %v", err)
+			}
+
+			if len(content) != count {
+				t.Errorf("The destination %d of step %d should contain %d records, contains: %v", j, i, count, content)
+			}
+		}
+	}
+}
+
+func TestDoWhereExplodeOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "whereexplode")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds1": {
+			{"name": "Mewtwo", "legendary": "true", "generation": "1", "types": "psychic"},
+			{"name": "Pikachu", "legendary": "false", "generation": "1", "types": "electric"},
+			{"name": "Lugia", "legendary": "true", "generation": "2", "types": "psychic, flying"},
+			{"name": "Kyogre", "legendary": "true", "generation": "3", "types": "water"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "whereexplode", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	expected := [][2]string{{"Mewtwo", "psychic"}, {"Lugia", "psychic"}, {"Lugia", "flying"}}
+	if len(content) != len(expected) {
+		t.Fatalf("The destination should contain %d records, contains: %v", len(expected), content)
+	}
+
+	for i, e := range expected {
+		if content[i]["name"] != e[0] || content[i]["types"] != e[1] {
+			t.Errorf("The record %d should be %v, it was: %v", i, e, content[i])
+		}
+	}
+}
+
+func TestDoLookupFileOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "lookupfile")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds1": {
+			{"name": "Pikachu", "type": "E"},
+			{"name": "Missingno", "type": "?"},
+			{"name": "Squirtle", "type": "W"},
+		},
+		"dslookupfile": {
+			{"legacy": "E", "id": "13", "label": "electric"},
+			{"legacy": "W", "id": "11", "label": "water"},
+			{"legacy": "W", "id": "99", "label": "duplicate"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "lookupfile", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	expected := []map[string]string{
+		{"name": "Pikachu", "type": "13", "label": "electric"},
+		{"name": "Squirtle", "type": "11", "label": "water"},
+	}
+	if len(content) != len(expected) {
+		t.Fatalf("The destination should contain %d records, contains: %v", len(expected), content)
+	}
+
+	for i, e := range expected {
+		for col, value := range e {
+			if content[i][col] != value {
+				t.Errorf("The column %s of the record %d should be %s, it was: %v", col, i, value, content[i])
+			}
+		}
+	}
+}
+
+func TestDoLookupDatabaseOk(t *testing.T) {
+	ctx, log, dss, v, prov := setupDo("testdata/good/steps/", "lookupdb")
+
+	prov.Contents = map[string][]map[string]string{
+		"ds1": {
+			{"name": "Pikachu", "type": "E", "label": types.NullValue},
+			{"name": "Missingno", "type": "it's", "label": types.NullValue},
+			{"name": "Raichu", "type": "E", "label": "thunder"},
+		},
+		"dslookupdb(types)": {
+			{"code": "W", "label": "water"},
+			{"code": "E", "label": "electric"},
+		},
+	}
+
+	_, steps, err := sync.Load(ctx, log, "testdata/good", "lookupdb", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Fatalf("Load should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Init(ctx, log)
+	if err != nil {
+		t.Fatalf("Init should not returns an error, returned: %v", err)
+	}
+
+	err = steps[0].Do(context.Background(), log)
+	if err != nil {
+		t.Errorf("Do should not return error, returned: %v", err)
+	}
+
+	content, err := sync.DestinationContent(steps[0], 0)
+	if err != nil {
+		t.Fatalf("DestinationContent should not return error, returned: %v", err)
+	}
+
+	expected := []string{"electric", types.NullValue, "thunder"}
+	if len(content) != len(expected) {
+		t.Fatalf("The destination should contain %d records, contains: %v", len(expected), content)
+	}
+
+	for i, label := range expected {
+		if content[i]["label"] != label {
+			t.Errorf("The label of the record %d should be %s, it was: %v", i, label, content[i])
+		}
+	}
+
+	// The query is prepared once and the merge mode does not look for the records already having a value
+	if len(prov.Lookups) != 1 {
+		t.Fatalf("The lookup query should be prepared once, it was %d times", len(prov.Lookups))
+	}
+
+	for _, value := range []string{"E", "it's"} {
+		found := false
+
+		for _, f := range prov.Lookups[0].Fetched {
+			found = found || f == value
+		}
+
+		if !found {
+			t.Errorf("The lookup datasource should have been queried with %s, queries: %v", value, prov.Lookups[0].Fetched)
+		}
+	}
+
+	steps[0].Finish(log)
+
+	if prov.Lookups[0].Closed != 1 {
+		t.Errorf("The lookup query should be closed once by Finish, it was %d times", prov.Lookups[0].Closed)
+	}
+}
@@ -1,10 +1,14 @@
 package sync
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/Sirupsen/logrus"
+
 	"github.com/marema31/kamino/mockprovider"
 	"github.com/marema31/kamino/step/common"
+	"github.com/marema31/kamino/throttle"
 )
 
 func MockSourceContent(step common.Steper, content []map[string]string) error {
@@ -57,3 +61,108 @@ func MockDestinationError(step common.Steper) error {
 	s.ErrorSave = fmt.Errorf("fake error")
 	return nil
 }
+
+func MockDestinationSaveError(step common.Steper, saveErr error) error {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return fmt.Errorf("The step should be a sync step")
+	}
+
+	s, ok := st.destinations[0].(*mockprovider.MockSaver)
+
+	if !ok {
+		return fmt.Errorf("The source should be a mockSaver")
+	}
+
+	s.ErrorSave = saveErr
+	return nil
+}
+
+func PlanCounts(step common.Steper, index int) (inserted int, updated int, deleted int, unchanged int, skipped int, err error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return 0, 0, 0, 0, 0, fmt.Errorf("The step should be a sync step")
+	}
+
+	if index >= len(st.plans) {
+		return 0, 0, 0, 0, 0, fmt.Errorf("There is no plan for destination %d", index)
+	}
+
+	p := st.plans[index]
+
+	return p.inserted, p.updated, p.deleted, p.unchanged, p.skipped, nil
+}
+
+func DestinationContent(step common.Steper, index int) ([]map[string]string, error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return nil, fmt.Errorf("The step should be a sync step")
+	}
+
+	s, ok := st.destinations[index].(*mockprovider.MockSaver)
+
+	if !ok {
+		return nil, fmt.Errorf("The destination should be a mockSaver")
+	}
+
+	return s.Content, nil
+}
+
+func SaversTables(step common.Steper) ([]string, error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return nil, fmt.Errorf("The step should be a sync step")
+	}
+
+	tables := make([]string, 0, len(st.destinations))
+
+	for _, d := range st.destinations {
+		s, ok := d.(*mockprovider.MockSaver)
+		if !ok {
+			return nil, fmt.Errorf("The destination should be a mockSaver")
+		}
+
+		tables = append(tables, fmt.Sprintf("%s:%d", s.Table, len(s.Content)))
+	}
+
+	return tables, nil
+}
+
+func TrackerDescription(ctx context.Context, log *logrus.Entry, step common.Steper) (string, error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return "", fmt.Errorf("The step should be a sync step")
+	}
+
+	tracker := st.newTracker(ctx, log, st.sourceCfg)
+	if tracker == nil {
+		return "", fmt.Errorf("The step should report its progression")
+	}
+
+	return tracker.String(), nil
+}
+
+func Limiter(step common.Steper) (*throttle.Limiter, error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return nil, fmt.Errorf("The step should be a sync step")
+	}
+
+	return st.limiter, nil
+}
+
+func DestinationMode(step common.Steper, index int) (string, error) {
+	//For test purpose we must see what is inside the step and for this convert the interface to the presumed type
+	st, ok := step.(*Step)
+	if !ok {
+		return "", fmt.Errorf("The step should be a sync step")
+	}
+
+	return st.destsUsed[index].mode, nil
+}
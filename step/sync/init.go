@@ -6,6 +6,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
 	"github.com/marema31/kamino/step/common"
 )
 
@@ -18,18 +19,46 @@ func (st *Step) Init(ctx context.Context, log *logrus.Entry) error {
 	logStep.Debug("Initializing step")
 	logStep.Debug("Creating loader instance for source")
 
-	if st.forceCacheOnly && st.cacheCfg.ds != nil {
+	// The cache commands only need the source to refresh the cache
+	if st.cacheAction != "" && (st.cacheAction != cacheRefresh || st.cacheCfg.ds == nil) {
+		return nil
+	}
+
+	if st.forceCacheOnly && st.cacheCfg.ds != nil && st.cacheAction == "" {
 		logStep.Info("Cache usage forced")
 
-		st.source, err = st.prov.NewLoader(ctx, log, st.cacheCfg.ds, st.cacheCfg.table, "")
+		if !st.cacheMatches() {
+			logStep.Warn("The cache has been created with another definition of the source, where clause or filters")
+		}
+
+		st.source, err = st.prov.NewLoader(ctx, log, st.cacheCfg.ds, st.cacheCfg.table, "", types.LoaderOptions{})
 		st.cacheCfg.ds = nil
+		st.fromCache = true
 	} else {
-		st.source, err = st.prov.NewLoader(ctx, log, st.sourceCfg.ds, st.sourceCfg.table, st.sourceCfg.where)
-		if err != nil && st.allowCacheOnly && st.cacheCfg.ds != nil {
+		var options types.LoaderOptions
+
+		if st.checkpoint != nil {
+			options, err = st.checkpoint.load(logStep, st.destsCfg)
+			if err != nil {
+				return err
+			}
+		}
+
+		options.ChunkSize = st.sourceCfg.chunkSize
+		options.ChunkPause = st.sourceCfg.chunkPause
+
+		where := st.sourceCfg.where
+		if st.subset != nil {
+			where = st.subset.sampleWhere(st.sourceCfg.ds.GetEngine(), where)
+		}
+
+		st.source, err = st.newSourceLoader(ctx, log, where, options)
+		if err != nil && st.allowCacheOnly && st.cacheCfg.ds != nil && st.cacheAction == "" {
 			logStep.Info("Source not available, I will use the cache")
 
-			st.source, err = st.prov.NewLoader(ctx, log, st.cacheCfg.ds, st.cacheCfg.table, "")
+			st.source, err = st.prov.NewLoader(ctx, log, st.cacheCfg.ds, st.cacheCfg.table, "", types.LoaderOptions{})
 			st.cacheCfg.ds = nil
+			st.fromCache = true
 		}
 	}
 
@@ -37,10 +66,21 @@ func (st *Step) Init(ctx context.Context, log *logrus.Entry) error {
 		return err
 	}
 
+	// The lookup queries are prepared once for the whole synchronization
+	if err = st.openLookups(ctx); err != nil {
+		return err
+	}
+
+	if st.cacheAction != "" {
+		return nil
+	}
+
 	log.Debug("Creating saver instances for destinations")
 
 	savers := make([]provider.Saver, 0, len(st.destsCfg))
 
+	var columns []types.Column
+
 	for _, dest := range st.destsCfg {
 		skip, err := common.ToSkipDatabase(ctx, logStep, dest.ds, false, false, dest.queries)
 
@@ -48,14 +88,49 @@ func (st *Step) Init(ctx context.Context, log *logrus.Entry) error {
 			return fmt.Errorf("unable to determine is this destination must be skipped, %w", err)
 		}
 
-		if !skip {
-			saver, err := st.prov.NewSaver(ctx, log, dest.ds, dest.table, dest.key, dest.mode)
-			if err != nil {
-				return err
+		if skip {
+			continue
+		}
+
+		if st.checkpoint != nil {
+			dest = st.checkpoint.addDestination(logStep, dest)
+		}
+
+		st.destsUsed = append(st.destsUsed, dest)
+
+		// In dry run, the destinations are only read by Do to determine what would be done
+		// In subset mode, the savers are created by Do for each table selected
+		if st.dryRun || st.subset != nil {
+			continue
+		}
+
+		options := types.SaverOptions{
+			Merge:              dest.merge,
+			SoftDelete:         dest.softDelete,
+			ResetSequence:      dest.resetSeq,
+			DisableForeignKeys: dest.disableFKs,
+			DisableTriggers:    dest.disableTrig,
+			TolerateErrors:     st.maxErrors != 0,
+		}
+
+		if dest.createTable {
+			if columns == nil {
+				columns, err = st.source.Columns(logStep)
+				if err != nil {
+					return fmt.Errorf("unable to determine the columns of the source, %w", err)
+				}
 			}
 
-			savers = append(savers, saver)
+			options.CreateTable = true
+			options.Columns = columns
 		}
+
+		saver, err := st.prov.NewSaver(ctx, log, dest.ds, dest.table, dest.key, dest.mode, options)
+		if err != nil {
+			return err
+		}
+
+		savers = append(savers, saver)
 	}
 
 	st.destinations = savers
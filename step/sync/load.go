@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -13,27 +15,40 @@ import (
 	"github.com/marema31/kamino/datasource"
 	"github.com/marema31/kamino/filter"
 	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
 	"github.com/marema31/kamino/step/common"
+	"github.com/marema31/kamino/throttle"
 )
 
 // SourceConfig type for source contain all possible fields without verification.
 type SourceConfig struct {
-	Tags    []string
-	Engines []string
-	Types   []string
-	Table   string
-	Where   string
+	Tags       []string
+	Engines    []string
+	Types      []string
+	Table      string
+	Where      string
+	ChunkSize  int
+	ChunkPause time.Duration
+	Union      bool
+	Origin     string
+	PairBy     string
 }
 
 // DestinationConfig type for destination contain all possible fields without verification.
 type DestinationConfig struct {
-	Tags    []string
-	Engines []string
-	Types   []string
-	Table   string
-	Key     string
-	Mode    string
-	Queries []string
+	Tags               []string
+	Engines            []string
+	Types              []string
+	Table              string
+	Key                string
+	Mode               string
+	Queries            []string
+	CreateTable        bool
+	Merge              types.MergePolicy
+	SoftDelete         types.SoftDelete
+	ResetSequence      bool
+	DisableForeignKeys bool
+	DisableTriggers    bool
 }
 
 // FilterConfig type for filter contain all possible fields without verification.
@@ -41,6 +56,7 @@ type FilterConfig struct {
 	Aparameters []string
 	Mparameters map[string]string
 	Type        string
+	Lookup      LookupConfig
 }
 
 var errDatasource = errors.New("NOT CORRECT NUMBER OF DATASOURCES")
@@ -95,7 +111,12 @@ func parseSourceConfig(log *logrus.Entry, objectType string, v *viper.Viper, dss
 		return parsedLimitedSource, parsedNotLimitedSource, err
 	}
 
-	limited, notLimited, err := getDatasources(log, dss, source.Tags, source.Engines, source.Types, objectType, true, nil)
+	if source.Union && source.PairBy != "" {
+		log.Errorf("The %s can not use union and pairBy together", objectType)
+		return parsedLimitedSource, parsedNotLimitedSource, fmt.Errorf("%s can not use union and pairBy together: %w", objectType, common.ErrWrongParameterValue)
+	}
+
+	limited, notLimited, err := getDatasources(log, dss, source.Tags, source.Engines, source.Types, objectType, !source.Union && source.PairBy == "", nil)
 	if err != nil {
 		return parsedLimitedSource, parsedNotLimitedSource, err
 	}
@@ -103,9 +124,24 @@ func parseSourceConfig(log *logrus.Entry, objectType string, v *viper.Viper, dss
 	parsedLimitedSource.ds = limited[0]
 	parsedLimitedSource.table = source.Table
 	parsedLimitedSource.where = source.Where
+	parsedLimitedSource.chunkSize = source.ChunkSize
+	parsedLimitedSource.chunkPause = source.ChunkPause
 	parsedNotLimitedSource.ds = limited[0]
 	parsedNotLimitedSource.table = source.Table
 	parsedNotLimitedSource.where = source.Where
+	parsedNotLimitedSource.chunkSize = source.ChunkSize
+	parsedNotLimitedSource.chunkPause = source.ChunkPause
+
+	parsedLimitedSource.origin = source.Origin
+	parsedNotLimitedSource.origin = source.Origin
+
+	// With pairBy, the other datasources are only the candidates for the pairing
+	if source.Union || source.PairBy != "" {
+		parsedLimitedSource.union = limited[1:]
+		parsedLimitedSource.pairBy = source.PairBy
+		parsedNotLimitedSource.union = limited[1:]
+		parsedNotLimitedSource.pairBy = source.PairBy
+	}
 
 	if len(notLimited) != 0 {
 		parsedNotLimitedSource.ds = notLimited[0]
@@ -119,12 +155,34 @@ func addParsedDest(log *logrus.Entry, parseDests []parsedDestConfig, datasource
 	p.ds = datasource
 	p.table = dest.Table
 	p.key = dest.Key
+	p.createTable = dest.CreateTable
+	p.resetSeq = dest.ResetSequence
+	p.disableFKs = dest.DisableForeignKeys
+	p.disableTrig = dest.DisableTriggers
 
 	p.mode = strings.ToLower(dest.Mode)
 	if p.mode == "onlyifempty" && force {
 		p.mode = "truncate"
 	}
 
+	if p.mode == "merge" {
+		merge, err := getMergePolicy(log, datasource, dest)
+		if err != nil {
+			return parseDests, err
+		}
+
+		p.merge = merge
+	}
+
+	if dest.SoftDelete.Column != "" {
+		softDelete, err := getSoftDelete(log, p, dest)
+		if err != nil {
+			return parseDests, err
+		}
+
+		p.softDelete = softDelete
+	}
+
 	tmplValues := datasource.FillTmplValues()
 
 	queries, err := common.RenderQueries(log, tqueries, tmplValues)
@@ -137,6 +195,74 @@ func addParsedDest(log *logrus.Entry, parseDests []parsedDestConfig, datasource
 	return append(parseDests, p), err
 }
 
+//getMergePolicy verify the merge policy of a destination and return its normalized version.
+func getMergePolicy(log *logrus.Entry, ds datasource.Datasourcer, dest DestinationConfig) (types.MergePolicy, error) {
+	merge := types.MergePolicy{
+		Policy:    strings.ToLower(dest.Merge.Policy),
+		Timestamp: dest.Merge.Timestamp,
+		Columns:   make(map[string]string),
+	}
+
+	if ds.GetType() != datasource.Database {
+		log.Errorf("Merge mode needs a database destination, %s is not", ds.GetName())
+		return merge, fmt.Errorf("merge mode needs a database destination: %w", common.ErrWrongParameterValue)
+	}
+
+	if dest.Key == "" {
+		log.Errorf("Merge mode needs a key for %s", ds.GetName())
+		return merge, fmt.Errorf("merge mode needs a key: %w", common.ErrMissingParameter)
+	}
+
+	if merge.Policy == "" {
+		merge.Policy = types.MergeSource
+	}
+
+	for col, policy := range dest.Merge.Columns {
+		merge.Columns[strings.ToLower(col)] = strings.ToLower(policy)
+	}
+
+	policies := []string{merge.Policy}
+	for _, policy := range merge.Columns {
+		policies = append(policies, policy)
+	}
+
+	for _, policy := range policies {
+		switch policy {
+		case types.MergeSource, types.MergeDestination:
+		case types.MergeNewest:
+			if merge.Timestamp == "" {
+				log.Error("Merge policy newest needs a timestamp column")
+				return merge, fmt.Errorf("merge policy newest needs a timestamp column: %w", common.ErrMissingParameter)
+			}
+		default:
+			log.Errorf("Unknown merge policy %s", policy)
+			return merge, fmt.Errorf("unknown merge policy %s: %w", policy, common.ErrWrongParameterValue)
+		}
+	}
+
+	return merge, nil
+}
+
+//getSoftDelete verify the soft delete of a destination and return it with the default values.
+func getSoftDelete(log *logrus.Entry, p parsedDestConfig, dest DestinationConfig) (types.SoftDelete, error) {
+	softDelete := dest.SoftDelete
+
+	if p.ds.GetType() != datasource.Database || planMode(p) != "exactcopy" {
+		log.Errorf("Soft delete needs a database destination in exactCopy mode, %s is not", p.ds.GetName())
+		return softDelete, fmt.Errorf("soft delete needs a database destination in exactCopy mode: %w", common.ErrWrongParameterValue)
+	}
+
+	if softDelete.Deleted == "" {
+		softDelete.Deleted = "CURRENT_TIMESTAMP"
+	}
+
+	if softDelete.Alive == "" {
+		softDelete.Alive = "NULL"
+	}
+
+	return softDelete, nil
+}
+
 func parseDestConfig(log *logrus.Entry, v *viper.Viper, dss datasource.Datasourcers, force bool, limitedTags []string) ([]parsedDestConfig, []parsedDestConfig, error) {
 	var dests []DestinationConfig
 
@@ -178,20 +304,46 @@ func parseDestConfig(log *logrus.Entry, v *viper.Viper, dss datasource.Datasourc
 	return parsedLimitedDests, parsedNotLimitedDests, nil
 }
 
-func getFilters(log *logrus.Entry, v *viper.Viper) ([]filter.Filter, error) {
+func getFilters(ctx context.Context, log *logrus.Entry, v *viper.Viper, recipePath string, dss datasource.Datasourcers, prov provider.Provider) ([]filter.Filter, []*lookupSource, error) {
 	fcs := make([]FilterConfig, 0)
 	filters := make([]filter.Filter, 0)
+	lookups := make([]*lookupSource, 0)
 
 	err := v.UnmarshalKey("filters", &fcs)
 	if err != nil {
 		log.Error(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	for _, fc := range fcs {
-		f, err := filter.NewFilter(log, fc.Type, fc.Aparameters, fc.Mparameters)
+		var (
+			f   filter.Filter
+			err error
+		)
+
+		// The key file of the crypt filters is relative to the recipe folder
+		if fc.Type == "encrypt" || fc.Type == "decrypt" {
+			for name, file := range fc.Mparameters {
+				if strings.EqualFold(name, "keyfile") && file != "" && !filepath.IsAbs(file) {
+					fc.Mparameters[name] = filepath.Join(recipePath, file)
+				}
+			}
+		}
+
+		// The lookup filter is the only one needing a datasource
+		if fc.Type == "lookup" {
+			var ls *lookupSource
+
+			f, ls, err = newLookupFilter(ctx, log, dss, prov, fc)
+			if err == nil {
+				lookups = append(lookups, ls)
+			}
+		} else {
+			f, err = filter.NewFilter(log, fc.Type, fc.Aparameters, fc.Mparameters)
+		}
+
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		filters = append(filters, f)
@@ -199,7 +351,99 @@ func getFilters(log *logrus.Entry, v *viper.Viper) ([]filter.Filter, error) {
 
 	log.Debugf("Found %d filters", len(filters))
 
-	return filters, nil
+	return filters, lookups, nil
+}
+
+//checkExplodedKeys refuse the destinations identifying the rows by their key if the records generated by an explode filter would have the same key.
+func checkExplodedKeys(log *logrus.Entry, filters []filter.Filter, dests []parsedDestConfig) error {
+	for _, f := range filters {
+		e, ok := f.(filter.Exploder)
+		if !ok {
+			continue
+		}
+
+		for _, dest := range dests {
+			switch dest.mode {
+			case "onlyifempty", "insert", "truncate":
+				continue
+			}
+
+			if dest.ds.GetType() != datasource.Database || dest.key == "" {
+				continue
+			}
+
+			exploded := false
+
+			for _, col := range e.Exploded() {
+				if strings.EqualFold(col, dest.key) {
+					exploded = true
+				}
+			}
+
+			if !exploded {
+				log.Errorf("The records generated by explode would have the same key %s in %s with mode %s", dest.key, dest.ds.GetName(), dest.mode)
+				return fmt.Errorf("explode generates records with the same key %s for the mode %s of %s: %w", dest.key, dest.mode, dest.ds.GetName(), common.ErrWrongParameterValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+func getCheckpoint(log *logrus.Entry, v *viper.Viper, recipePath string, name string, nameIndex int) (*checkpointConfig, error) {
+	if v.IsSet("cache") {
+		log.Error("Checkpoint and cache can not be used together")
+		return nil, fmt.Errorf("checkpoint and cache can not be used together: %w", common.ErrWrongParameterValue)
+	}
+
+	cp := checkpointConfig{
+		key:      v.GetString("checkpoint.key"),
+		file:     v.GetString("checkpoint.file"),
+		interval: v.GetInt("checkpoint.interval"),
+	}
+
+	if cp.key == "" {
+		log.Error("No checkpoint key provided")
+		return nil, fmt.Errorf("no checkpoint key definition: %w", common.ErrMissingParameter)
+	}
+
+	if cp.file == "" {
+		cp.file = fmt.Sprintf("%s_%d.checkpoint", name, nameIndex)
+	}
+
+	if !filepath.IsAbs(cp.file) {
+		cp.file = filepath.Join(recipePath, cp.file)
+	}
+
+	if cp.interval <= 0 {
+		cp.interval = defaultCheckpointInterval
+	}
+
+	return &cp, nil
+}
+
+func getSubset(log *logrus.Entry, v *viper.Viper) (*subsetConfig, error) {
+	if v.IsSet("cache") || v.IsSet("checkpoint") || v.IsSet("verify") {
+		log.Error("Subset can not be used with cache, checkpoint or verify")
+		return nil, fmt.Errorf("subset can not be used with cache, checkpoint or verify: %w", common.ErrWrongParameterValue)
+	}
+
+	ss := subsetConfig{
+		percent: v.GetFloat64("subset.percent"),
+		limit:   v.GetInt("subset.limit"),
+		exclude: make(map[string]bool),
+	}
+
+	if ss.percent < 0 || ss.percent > 100 {
+		log.Errorf("Subset percent must be between 0 and 100, not %g", ss.percent)
+		return nil, fmt.Errorf("subset percent must be between 0 and 100: %w", common.ErrWrongParameterValue)
+	}
+
+	for _, table := range v.GetStringSlice("subset.exclude") {
+		ss.exclude[table] = true
+	}
+
+	return &ss, nil
 }
 
 //PostLoad modify the loaded step values with the values provided in the map in argument.
@@ -208,6 +452,15 @@ func (st *Step) PostLoad(log *logrus.Entry, superseed map[string]string) (err er
 		st.forceCacheOnly, err = strconv.ParseBool(value)
 	}
 
+	if value, ok := superseed["sync.cacheAction"]; ok {
+		switch value {
+		case cacheList, cacheRefresh, cacheClear:
+			st.cacheAction = value
+		default:
+			return fmt.Errorf("unknown cache action %s: %w", value, common.ErrWrongParameterValue)
+		}
+	}
+
 	return err
 }
 
@@ -223,6 +476,7 @@ func Load(ctx context.Context, log *logrus.Entry, recipePath string, name string
 	logStep := log.WithField("name", name).WithField("type", "shell")
 	step.Name = fmt.Sprintf("%s:%d", name, nameIndex)
 	step.dryRun = dryRun
+	step.dryRunSample = v.GetInt("dryRunSample")
 
 	if !v.IsSet("source") {
 		logStep.Error("No source provided")
@@ -259,12 +513,60 @@ func Load(ctx context.Context, log *logrus.Entry, recipePath string, name string
 	log.Debug("Lookup filters")
 
 	if v.IsSet("filters") {
-		step.filters, err = getFilters(logStep, v)
+		step.filters, step.lookups, err = getFilters(ctx, logStep, v, recipePath, dss, provider)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if v.IsSet("checkpoint") && !dryRun {
+		step.checkpoint, err = getCheckpoint(logStep, v, recipePath, name, nameIndex)
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if v.IsSet("subset") {
+		step.subset, err = getSubset(logStep, v)
 		if err != nil {
 			return 0, nil, err
 		}
 	}
 
+	step.maxErrors = v.GetInt("maxErrors")
+
+	if v.IsSet("progress") {
+		step.progress = &progressConfig{count: v.GetBool("progress.count"), interval: v.GetDuration("progress.interval")}
+		if step.progress.interval <= 0 {
+			step.progress.interval = defaultProgressInterval
+		}
+	}
+
+	if v.IsSet("rejects") && !dryRun {
+		_, step.rejectsCfg, err = parseSourceConfig(logStep, "rejects", v.Sub("rejects"), dss)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if step.rejectsCfg.ds.GetType() != datasource.File {
+			logStep.Error("Rejects must be a file datasource")
+			return 0, nil, fmt.Errorf("rejects must be a file datasource: %w", common.ErrWrongParameterValue)
+		}
+	}
+
+	for _, f := range step.filters {
+		if r, ok := f.(filter.Rejecter); ok && r.Rejects() && !dryRun && step.rejectsCfg.ds == nil {
+			logStep.Error("A filter rejecting records needs a rejects datasource")
+			return 0, nil, fmt.Errorf("a filter rejecting records needs a rejects datasource: %w", common.ErrMissingParameter)
+		}
+	}
+
+	step.limiter = throttle.New(v.GetInt("throttle.rows"), v.GetInt("throttle.bytes"))
+
+	if v.IsSet("verify") {
+		step.verify = &verifyConfig{columns: v.GetStringSlice("verify.columns")}
+	}
+
 	log.Debug("Lookup destinations")
 
 	parsedLimitedDestsCfg, parsedNotLimitedDestsCfg, err := parseDestConfig(logStep, v, dss, force, limitedTags)
@@ -301,6 +603,38 @@ func Load(ctx context.Context, log *logrus.Entry, recipePath string, name string
 		return 0, nil, fmt.Errorf("no destination found: %w", errDatasource)
 	}
 
+	if err = checkExplodedKeys(logStep, step.filters, step.destsCfg); err != nil {
+		return 0, nil, err
+	}
+
+	if step.cacheCfg.ds != nil {
+		step.cacheKey = cacheKey(step.sourceCfg, v.Get("filters"))
+	}
+
+	if step.subset != nil && step.sourceCfg.ds.GetType() != datasource.Database {
+		log.Error("Subset needs a database source")
+		return 0, nil, fmt.Errorf("subset needs a database source: %w", common.ErrWrongParameterValue)
+	}
+
+	if len(step.sourceCfg.union) != 0 && step.sourceCfg.pairBy == "" && (step.subset != nil || step.checkpoint != nil) {
+		log.Error("A union of sources can not be used with subset or checkpoint")
+		return 0, nil, fmt.Errorf("a union of sources can not be used with subset or checkpoint: %w", common.ErrWrongParameterValue)
+	}
+
+	if step.checkpoint != nil && step.sourceCfg.ds.GetType() != datasource.Database {
+		log.Error("Checkpoint needs a database source")
+		return 0, nil, fmt.Errorf("checkpoint needs a database source: %w", common.ErrWrongParameterValue)
+	}
+
+	if step.sourceCfg.pairBy != "" {
+		steps, err = pairSteps(ctx, logStep, dss, &step, v, name, nameIndex)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		return priority, steps, nil
+	}
+
 	steps = append(steps, &step)
 
 	return priority, steps, nil
@@ -199,6 +199,81 @@ func TestSyncTooManySourceDatasource(t *testing.T) {
 
 }
 
+func TestSyncWrongMergePolicy(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/fail/steps/", "wrongmerge")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	_, _, err = sync.Load(ctx, log, "testdata/fail", "wrongmerge", 0, v, dss, prov, false, false, nil)
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+
+}
+
+func TestSyncWrongSoftDeleteMode(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/fail/steps/", "wrongsoftdelete")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	_, _, err = sync.Load(ctx, log, "testdata/fail", "wrongsoftdelete", 0, v, dss, prov, false, false, nil)
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+
+}
+
+func TestSyncWrongLookup(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/fail/steps/", "wronglookup")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	_, _, err = sync.Load(ctx, log, "testdata/fail", "wronglookup", 0, v, dss, prov, false, false, nil)
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+}
+
+func TestSyncValidateWithoutRejects(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/fail/steps/", "wrongvalidate")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	_, _, err = sync.Load(ctx, log, "testdata/fail", "wrongvalidate", 0, v, dss, prov, false, false, nil)
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+}
+
+func TestSyncExplodeKeyedMode(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/fail/steps/", "wrongexplode")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	_, _, err = sync.Load(ctx, log, "testdata/fail", "wrongexplode", 0, v, dss, prov, false, false, nil)
+	if err == nil {
+		t.Errorf("Load should returns an error")
+	}
+}
+
+func TestSyncCryptKeyFileOk(t *testing.T) {
+	ctx, log, dss, v, prov, err := setupLoad("testdata/good/steps/", "decrypt")
+	if err != nil {
+		t.Errorf("SetupLoad should not returns an error, returned: %v", err)
+	}
+
+	// The key file is found in the recipe folder, not in the working directory
+	_, _, err = sync.Load(ctx, log, "testdata/good", "decrypt", 0, v, dss, prov, false, false, nil)
+	if err != nil {
+		t.Errorf("Load should not returns an error, returned: %v", err)
+	}
+}
+
 func TestSyncPostLoadOk(t *testing.T) {
 	ctx, log, dss, v, prov, err := setupLoad("testdata/good/steps/", "syncok")
 	if err != nil {
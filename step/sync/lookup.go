@@ -0,0 +1,190 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
+	"github.com/marema31/kamino/step/common"
+)
+
+var errLookupNotOpened = errors.New("LOOKUP NOT OPENED")
+
+// LookupConfig type for the datasource of the lookup filter contain all possible fields without verification.
+type LookupConfig struct {
+	Tags      []string
+	Engines   []string
+	Types     []string
+	Table     string
+	Key       string
+	Column    string
+	Mode      string
+	Miss      string
+	CacheSize int
+}
+
+//lookupSource fetches the records of the datasource of a lookup filter.
+type lookupSource struct {
+	ctx   context.Context
+	log   *logrus.Entry
+	prov  provider.Provider
+	ds    datasource.Datasourcer
+	table string
+	key   string
+	// Prepared query of a database datasource, opened by the Init of the step
+	lookup provider.Lookup
+	// Content of a file datasource, loaded on first use
+	records map[string]types.Record
+}
+
+//open prepare the query of a database datasource, the file datasources are read on first use.
+func (ls *lookupSource) open(ctx context.Context) error {
+	if ls.ds.GetType() != datasource.Database || ls.lookup != nil {
+		return nil
+	}
+
+	lookup, err := ls.prov.NewLookup(ctx, ls.log, ls.ds, ls.table, ls.key)
+	if err != nil {
+		return err
+	}
+
+	ls.lookup = lookup
+
+	return nil
+}
+
+//close closes the prepared query of a database datasource.
+func (ls *lookupSource) close() {
+	if ls.lookup == nil {
+		return
+	}
+
+	if err := ls.lookup.Close(ls.log); err != nil {
+		ls.log.Error(err)
+	}
+
+	ls.lookup = nil
+}
+
+//load read the records of the datasource and call the function on each one until it returns false.
+func (ls *lookupSource) load(f func(types.Record) bool) error {
+	loader, err := ls.prov.NewLoader(ls.ctx, ls.log, ls.ds, ls.table, "", types.LoaderOptions{})
+	if err != nil {
+		return err
+	}
+
+	for loader.Next() {
+		record, err := loader.Load(ls.log)
+		if err != nil {
+			loader.Close(ls.log) //nolint: errcheck
+			return err
+		}
+
+		if !f(record) {
+			break
+		}
+	}
+
+	return loader.Close(ls.log)
+}
+
+//fetch return the record having the key value, a database is queried for each value while a file is read only once.
+func (ls *lookupSource) fetch(value string) (types.Record, error) {
+	if ls.ds.GetType() == datasource.Database {
+		if ls.lookup == nil {
+			return nil, fmt.Errorf("the lookup datasource %s is not opened: %w", ls.ds.GetName(), errLookupNotOpened)
+		}
+
+		return ls.lookup.Fetch(ls.log, value)
+	}
+
+	if ls.records == nil {
+		ls.log.Debugf("Loading the lookup datasource %s", ls.ds.GetName())
+
+		records := make(map[string]types.Record)
+
+		err := ls.load(func(record types.Record) bool {
+			// Only the first record of a key is kept
+			if _, ok := records[record[ls.key]]; !ok {
+				records[record[ls.key]] = record
+			}
+
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ls.records = records
+	}
+
+	return ls.records[value], nil
+}
+
+//openLookups prepare the queries of the database datasources of the lookup filters.
+func (st *Step) openLookups(ctx context.Context) error {
+	for _, ls := range st.lookups {
+		if err := ls.open(ctx); err != nil {
+			st.closeLookups()
+			return err
+		}
+	}
+
+	return nil
+}
+
+//closeLookups closes the prepared queries of the lookup filters.
+func (st *Step) closeLookups() {
+	for _, ls := range st.lookups {
+		ls.close()
+	}
+}
+
+//newLookupFilter return a lookup filter using the datasource described by its configuration.
+func newLookupFilter(ctx context.Context, log *logrus.Entry, dss datasource.Datasourcers, prov provider.Provider, fc FilterConfig) (filter.Filter, *lookupSource, error) {
+	cfg := fc.Lookup
+
+	if cfg.Key == "" {
+		log.Error("No key provided for the lookup filter")
+		return nil, nil, fmt.Errorf("no key for the lookup filter: %w", common.ErrMissingParameter)
+	}
+
+	lookups, _, err := getDatasources(log, dss, cfg.Tags, cfg.Engines, cfg.Types, "lookup", true, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lookups[0].GetType() == datasource.Database && cfg.Table == "" {
+		log.Error("No table provided for the lookup filter")
+		return nil, nil, fmt.Errorf("no table for the lookup filter: %w", common.ErrMissingParameter)
+	}
+
+	source := &lookupSource{
+		ctx:   ctx,
+		log:   log.WithField("lookup", lookups[0].GetName()),
+		prov:  prov,
+		ds:    lookups[0],
+		table: cfg.Table,
+		key:   cfg.Key,
+	}
+
+	column := cfg.Column
+	if column == "" {
+		column = cfg.Key
+	}
+
+	options := filter.LookupOptions{Column: column, Mode: cfg.Mode, Miss: cfg.Miss, CacheSize: cfg.CacheSize}
+
+	f, err := filter.NewLookupFilter(log, source.fetch, options, fc.Mparameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, source, nil
+}
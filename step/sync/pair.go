@@ -0,0 +1,91 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/step/common"
+	"github.com/marema31/kamino/throttle"
+)
+
+//pairSteps return a step by source, each one synchronizing the destinations having the same value of the named tag than its source.
+func pairSteps(ctx context.Context, log *logrus.Entry, dss datasource.Datasourcers, step *Step, v *viper.Viper, name string, nameIndex int) ([]common.Steper, error) {
+	pairBy := step.sourceCfg.pairBy
+
+	// These features use a file or a state that would be shared by all the steps
+	if step.cacheCfg.ds != nil || step.checkpoint != nil || step.subset != nil || step.rejectsCfg.ds != nil {
+		log.Error("Paired synchronization can not be used with cache, checkpoint, subset or rejects")
+		return nil, fmt.Errorf("paired synchronization can not be used with cache, checkpoint, subset or rejects: %w", common.ErrWrongParameterValue)
+	}
+
+	sources := make(map[string]datasource.Datasourcer)
+	order := make([]string, 0)
+
+	for _, ds := range step.sourceCfg.datasources() {
+		value := ds.GetNamedTag(pairBy)
+		if value == "" {
+			log.Errorf("Source %s does not have a %s tag", ds.GetName(), pairBy)
+			return nil, fmt.Errorf("source %s does not have a %s tag: %w", ds.GetName(), pairBy, errDatasource)
+		}
+
+		if other, ok := sources[value]; ok {
+			log.Errorf("Sources %s and %s have the same tag %s:%s", other.GetName(), ds.GetName(), pairBy, value)
+			return nil, fmt.Errorf("sources %s and %s have the same tag %s:%s: %w", other.GetName(), ds.GetName(), pairBy, value, errDatasource)
+		}
+
+		sources[value] = ds
+		order = append(order, value)
+	}
+
+	dests := make(map[string][]parsedDestConfig)
+
+	for _, dest := range step.destsCfg {
+		value := dest.ds.GetNamedTag(pairBy)
+		if _, ok := sources[value]; !ok {
+			log.Errorf("No source with tag %s:%s for destination %s", pairBy, value, dest.ds.GetName())
+			return nil, fmt.Errorf("no source with tag %s:%s for destination %s: %w", pairBy, value, dest.ds.GetName(), errDatasource)
+		}
+
+		dests[value] = append(dests[value], dest)
+	}
+
+	steps := make([]common.Steper, 0, len(dests))
+
+	for _, value := range order {
+		if len(dests[value]) == 0 {
+			log.Debugf("No destination with tag %s:%s, skipping source %s", pairBy, value, sources[value].GetName())
+			continue
+		}
+
+		paired := *step
+		paired.Name = fmt.Sprintf("%s:%d", name, nameIndex+len(steps))
+		paired.sourceCfg.ds = sources[value]
+		paired.sourceCfg.union = nil
+		paired.destsCfg = dests[value]
+		paired.destinations, paired.destsUsed, paired.plans = nil, nil, nil
+
+		// The limiter, the filters and the verification keep a state, each step needs its own
+		paired.limiter = throttle.New(v.GetInt("throttle.rows"), v.GetInt("throttle.bytes"))
+
+		if v.IsSet("filters") {
+			filters, lookups, err := getFilters(ctx, log, v, step.baseFolder, dss, step.prov)
+			if err != nil {
+				return nil, err
+			}
+
+			paired.filters, paired.lookups = filters, lookups
+		}
+
+		if step.verify != nil {
+			paired.verify = &verifyConfig{columns: step.verify.columns}
+		}
+
+		steps = append(steps, &paired)
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,286 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// destPlan contains what a synchronization would do on a destination.
+type destPlan struct {
+	name       string
+	mode       string
+	key        string
+	merge      types.MergePolicy
+	softDelete bool
+	columns    map[string]bool
+	rows       map[string]types.Record
+	count      int
+	sample     int
+	samples    []string
+	inserted   int
+	updated    int
+	deleted    int
+	unchanged  int
+	skipped    int
+}
+
+//planMode return the mode used to compute the plan, files are always rewritten entirely.
+func planMode(dest parsedDestConfig) string {
+	if dest.ds.GetType() == datasource.File {
+		if dest.key != "" {
+			return "exactcopy"
+		}
+
+		return "truncate"
+	}
+
+	switch dest.mode {
+	case "onlyifempty", "insert", "update", "replace", "truncate", "merge":
+		return dest.mode
+	default:
+		return "exactcopy"
+	}
+}
+
+func (p *destPlan) keyed() bool {
+	return p.mode == "update" || p.mode == "replace" || p.mode == "exactcopy" || p.mode == "merge"
+}
+
+func (p *destPlan) addSample(format string, args ...interface{}) {
+	if len(p.samples) < p.sample {
+		p.samples = append(p.samples, fmt.Sprintf(format, args...))
+	}
+}
+
+//loadDestPlan read the current content of the destination.
+func (st *Step) loadDestPlan(ctx context.Context, log *logrus.Entry, dest parsedDestConfig) (*destPlan, error) {
+	p := &destPlan{
+		name:       fmt.Sprintf("%s(%s)", dest.ds.GetName(), dest.table),
+		mode:       planMode(dest),
+		key:        dest.key,
+		merge:      dest.merge,
+		softDelete: dest.softDelete.Column != "",
+		columns:    make(map[string]bool),
+		rows:       make(map[string]types.Record),
+		sample:     st.dryRunSample,
+	}
+
+	if p.keyed() && p.key == "" {
+		log.Errorf("Mode %s need a key for %s", p.mode, p.name)
+		return nil, fmt.Errorf("mode %s need a key for %s: %w", p.mode, p.name, errDatasource)
+	}
+
+	if dest.ds.GetType() == datasource.File {
+		p.name = dest.ds.GetName()
+
+		if _, err := dest.ds.Stat(); os.IsNotExist(err) {
+			return p, nil
+		}
+	}
+
+	loader, err := st.prov.NewLoader(ctx, log, dest.ds, dest.table, "", types.LoaderOptions{})
+	if err != nil {
+		if dest.createTable {
+			log.Infof("Table %s would be created", p.name)
+			return p, nil
+		}
+
+		return nil, err
+	}
+
+	defer loader.Close(log)
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.count == 0 {
+			for col := range record {
+				p.columns[col] = true
+			}
+		}
+
+		p.count++
+
+		if p.keyed() {
+			p.rows[record[p.key]] = record
+		}
+	}
+
+	return p, nil
+}
+
+//changes return the description of columns that will be modified by the record.
+func (p *destPlan) changes(current types.Record, record types.Record) []string {
+	changes := make([]string, 0)
+
+	for col, value := range record {
+		if !p.columns[col] {
+			continue
+		}
+
+		if current[col] != value {
+			changes = append(changes, fmt.Sprintf("%s: '%s' => '%s'", col, current[col], value))
+		}
+	}
+
+	sort.Strings(changes)
+
+	return changes
+}
+
+//add determine what would be done with the record on the destination.
+func (p *destPlan) add(record types.Record) {
+	switch p.mode {
+	case "onlyifempty":
+		if p.count != 0 {
+			p.skipped++
+			return
+		}
+
+		p.inserted++
+		p.addSample("insert %v", record)
+	case "insert", "truncate":
+		p.inserted++
+		p.addSample("insert %v", record)
+	default:
+		id := record[p.key]
+
+		current, ok := p.rows[id]
+		if !ok {
+			if p.mode == "update" {
+				p.skipped++
+				return
+			}
+
+			p.inserted++
+			p.addSample("insert %v", record)
+
+			return
+		}
+
+		delete(p.rows, id)
+
+		if p.mode == "merge" {
+			record = p.merge.Resolve(current, record)
+		}
+
+		changes := p.changes(current, record)
+		if len(changes) == 0 {
+			p.unchanged++
+			return
+		}
+
+		p.updated++
+		p.addSample("update %s=%s: %s", p.key, id, strings.Join(changes, ", "))
+	}
+}
+
+//finish account the destination rows not present in source.
+func (p *destPlan) finish() {
+	switch p.mode {
+	case "truncate":
+		p.deleted = p.count
+	case "insert", "onlyifempty":
+		p.unchanged = p.count
+	case "exactcopy":
+		p.deleted = len(p.rows)
+
+		ids := make([]string, 0, len(p.rows))
+		for id := range p.rows {
+			ids = append(ids, id)
+		}
+
+		sort.Strings(ids)
+
+		action := "delete"
+		if p.softDelete {
+			action = "soft delete"
+		}
+
+		for _, id := range ids {
+			p.addSample("%s %s=%s", action, p.key, id)
+		}
+	default:
+		p.unchanged += len(p.rows)
+	}
+}
+
+//plan compute, without modifying them, what the synchronization would do on each destination.
+func (st *Step) plan(ctx context.Context, log *logrus.Entry) error {
+	log.Infof("Dry run: computing what would be synchronized from %s", st.source.Name())
+
+	plans := make([]*destPlan, 0, len(st.destsUsed))
+
+	for _, dest := range st.destsUsed {
+		p, err := st.loadDestPlan(ctx, log, dest)
+		if err != nil {
+			log.Error("Reading destination failed:")
+			log.Error(err)
+
+			return err
+		}
+
+		plans = append(plans, p)
+	}
+
+	for st.source.Next() {
+		record, err := st.source.Load(log)
+		if err != nil {
+			log.Error("Source reading failed:")
+			log.Error(err)
+
+			return err
+		}
+
+		// The rejected records are not written in dry-run mode, they are only ignored
+		records, _, err := filter.Apply(st.filters, record)
+		if err != nil {
+			log.Error("Filtering failed:")
+			log.Error(err)
+
+			return err
+		}
+
+		for _, record := range records {
+			for _, p := range plans {
+				p.add(record)
+			}
+		}
+
+		st.count++
+
+		//Look for cancellation between each data
+		select {
+		case <-ctx.Done(): //If the context has been cancelled stop the recipe execution here
+			log.Debug("Dry run cancelled")
+			return nil
+
+		default: // Make the poll to ctx.Done() non blocking. Do nothing
+		}
+	}
+
+	st.plans = plans
+
+	for _, p := range plans {
+		p.finish()
+
+		log.Infof("   - %s (%s): %d inserted, %d updated, %d deleted, %d unchanged, %d source rows skipped", p.name, p.mode, p.inserted, p.updated, p.deleted, p.unchanged, p.skipped)
+
+		for _, sample := range p.samples {
+			log.Infof("        %s", sample)
+		}
+	}
+
+	return nil
+}
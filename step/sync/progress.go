@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/progress"
+)
+
+const defaultProgressInterval = 10 * time.Second
+
+// progressConfig contains the parameters of the progression reporting.
+type progressConfig struct {
+	count    bool
+	interval time.Duration
+}
+
+//newTracker return the progression tracker of the synchronization, nil if the step does not report its progression.
+func (st *Step) newTracker(ctx context.Context, log *logrus.Entry, source parsedSourceConfig) *progress.Tracker {
+	if st.progress == nil {
+		return nil
+	}
+
+	if !st.progress.count {
+		return progress.NewTracker(st.Name, 0, progress.Rows, st.progress.interval)
+	}
+
+	var rows, bytes int64
+
+	countsRows, countsBytes := false, false
+
+	for _, ds := range source.datasources() {
+		switch {
+		case ds.GetType() == datasource.Database:
+			count, err := st.prov.Count(ctx, log, ds, source.table, source.where)
+			if err != nil {
+				log.Warnf("Counting the source rows failed, the progression will be displayed without total: %v", err)
+				return progress.NewTracker(st.Name, 0, progress.Rows, st.progress.interval)
+			}
+
+			rows += count
+			countsRows = true
+		case ds.GetType() == datasource.Synthetic:
+			gen, ok := ds.(datasource.GeneratorDatasourcer)
+			if !ok {
+				log.Warnf("The generator %s does not describe its tables, the progression will be displayed without total", ds.GetName())
+				return progress.NewTracker(st.Name, 0, progress.Rows, st.progress.interval)
+			}
+
+			rows += int64(gen.GetGenerator().Tables[source.table].Count)
+			countsRows = true
+		default:
+			stat, err := ds.Stat()
+			if err != nil {
+				log.Warnf("Measuring the source file failed, the progression will be displayed without total: %v", err)
+				return progress.NewTracker(st.Name, 0, progress.Rows, st.progress.interval)
+			}
+
+			// Only an estimation, the size of the records is not the size of their representation in the file
+			bytes += stat.Size()
+			countsBytes = true
+		}
+	}
+
+	// A total can only be followed in one unit
+	if countsRows && countsBytes {
+		log.Warn("The source mixes databases and files, the progression will be displayed without total")
+		return progress.NewTracker(st.Name, 0, progress.Rows, st.progress.interval)
+	}
+
+	if countsBytes {
+		return progress.NewTracker(st.Name, bytes, progress.Bytes, st.progress.interval)
+	}
+
+	return progress.NewTracker(st.Name, rows, progress.Rows, st.progress.interval)
+}
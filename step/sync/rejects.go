@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/filter"
+	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/common"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Columns added to the rejected records.
+const (
+	rejectDestinationColumn = "_destination"
+	rejectErrorColumn       = "_error"
+)
+
+//reject manages a record that can not be saved in a destination, return an error only if the synchronization must stop.
+func (st *Step) reject(ctx context.Context, log *logrus.Entry, dest provider.Saver, record types.Record, saveErr error) error {
+	// The destination is unusable, all the following records would fail
+	if errors.Is(saveErr, common.ErrSaverInit) {
+		log.Errorf("Preparing %s failed, the synchronization is stopped", dest.Name())
+		return saveErr
+	}
+
+	st.errors++
+
+	log.Warnf("Writing to %s failed: %v", dest.Name(), saveErr)
+
+	if st.rejectsCfg.ds != nil {
+		if err := st.writeReject(ctx, log, dest.Name(), record, saveErr); err != nil {
+			log.Error("Writing rejected record failed:")
+			log.Error(err)
+
+			return err
+		}
+	}
+
+	if st.maxErrors >= 0 && st.errors > st.maxErrors {
+		log.Errorf("Too many errors (%d), the synchronization is stopped", st.errors)
+		return fmt.Errorf("too many errors (%d), last one: %w", st.errors, saveErr)
+	}
+
+	return nil
+}
+
+//writeReject writes the record with the destination (or filter) name and the error message to the rejects datasource.
+func (st *Step) writeReject(ctx context.Context, log *logrus.Entry, dest string, record types.Record, saveErr error) error {
+	// The rejects file is only created if needed
+	if st.rejectsSaver == nil {
+		saver, err := st.prov.NewSaver(ctx, log, st.rejectsCfg.ds, st.rejectsCfg.table, "", "", types.SaverOptions{})
+		if err != nil {
+			return err
+		}
+
+		st.rejectsSaver = saver
+	}
+
+	rejected := make(types.Record, len(record)+2)
+	for col, value := range record {
+		rejected[col] = value
+	}
+
+	rejected[rejectDestinationColumn] = dest
+	rejected[rejectErrorColumn] = saveErr.Error()
+
+	return st.rejectsSaver.Save(log, rejected)
+}
+
+//applyFilters apply the filters on the record, the records rejected by a filter are written to the rejects datasource.
+func (st *Step) applyFilters(ctx context.Context, log *logrus.Entry, record types.Record) ([]types.Record, error) {
+	records, rejects, err := filter.Apply(st.filters, record)
+	if err != nil {
+		log.Error("Filtering failed:")
+		log.Error(err)
+
+		return nil, err
+	}
+
+	for _, reject := range rejects {
+		st.rejected++
+
+		log.Warnf("Record rejected by the %s filter: %s", reject.Filter, reject.Reason)
+
+		if err = st.writeReject(ctx, log, reject.Filter, reject.Record, reject); err != nil {
+			log.Error("Writing rejected record failed:")
+			log.Error(err)
+
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
+
+//closeRejects closes the rejects datasource, even on cancellation to keep the rejected records.
+func (st *Step) closeRejects(log *logrus.Entry) {
+	if st.errors != 0 {
+		log.Warnf("%d records can not be saved", st.errors)
+	}
+
+	if st.rejected != 0 {
+		log.Warnf("%d records rejected by the filters", st.rejected)
+	}
+
+	if st.rejectsSaver == nil {
+		return
+	}
+
+	if err := st.rejectsSaver.Close(log); err != nil {
+		log.Error(err)
+	}
+
+	st.rejectsSaver = nil
+}
@@ -0,0 +1,488 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
+)
+
+// Maximum number of values in one IN clause.
+const subsetBatchSize = 500
+
+// subsetTable contains the rows selected for a table.
+type subsetTable struct {
+	name    string
+	key     string
+	columns []types.Column
+	records []types.Record
+	seen    map[string]bool
+}
+
+// subsetConfig contains the sampling rules and the rows selected by a subset synchronization.
+type subsetConfig struct {
+	percent float64
+	limit   int
+	exclude map[string]bool
+	fks     []types.ForeignKey
+	tables  map[string]*subsetTable
+}
+
+// subsetPending contains rows whose relations must be followed.
+type subsetPending struct {
+	table   string
+	records []types.Record
+}
+
+//recordID return a string identifying the record.
+func recordID(record types.Record) string {
+	cols := make([]string, 0, len(record))
+	for col := range record {
+		cols = append(cols, col)
+	}
+
+	sort.Strings(cols)
+
+	var sb strings.Builder
+	for _, col := range cols {
+		fmt.Fprintf(&sb, "%s\x1f%s\x1e", col, record[col])
+	}
+
+	return sb.String()
+}
+
+//sampleWhere return the WHERE clause of the root table including the sampling percentage.
+func (ss *subsetConfig) sampleWhere(engine datasource.Engine, where string) string {
+	if ss.percent <= 0 || ss.percent >= 100 {
+		return where
+	}
+
+	random := "RAND()"
+	if engine == datasource.Postgres {
+		random = "random()"
+	}
+
+	sample := fmt.Sprintf("%s < %g", random, ss.percent/100)
+	if where == "" {
+		return sample
+	}
+
+	return fmt.Sprintf("(%s) AND %s", where, sample)
+}
+
+//table return the selection of the table, creating it if needed.
+func (ss *subsetConfig) table(name string) *subsetTable {
+	t, ok := ss.tables[name]
+	if !ok {
+		t = &subsetTable{name: name, seen: make(map[string]bool)}
+		ss.tables[name] = t
+	}
+
+	return t
+}
+
+//add the records to the table selection and return the ones not already selected.
+func (t *subsetTable) add(records []types.Record) []types.Record {
+	added := make([]types.Record, 0, len(records))
+
+	for _, record := range records {
+		id := recordID(record)
+		if t.seen[id] {
+			continue
+		}
+
+		t.seen[id] = true
+		t.records = append(t.records, record)
+		added = append(added, record)
+	}
+
+	return added
+}
+
+//setColumns keeps the description of the columns and the primary key of the table.
+func (t *subsetTable) setColumns(log *logrus.Entry, loader provider.Loader) error {
+	if t.columns != nil {
+		return nil
+	}
+
+	columns, err := loader.Columns(log)
+	if err != nil {
+		return err
+	}
+
+	t.columns = columns
+	keys := make([]string, 0, 1)
+
+	for _, col := range columns {
+		if col.PrimaryKey {
+			keys = append(keys, col.Name)
+		}
+	}
+
+	if len(keys) == 1 {
+		t.key = keys[0]
+	}
+
+	return nil
+}
+
+//tuples return the distinct values of the columns in the records, the ones containing NULL are ignored.
+func tuples(records []types.Record, columns []string) [][]string {
+	values := make([][]string, 0, len(records))
+	seen := make(map[string]bool)
+
+	for _, record := range records {
+		tuple := make([]string, 0, len(columns))
+
+		for _, col := range columns {
+			value, ok := record[col]
+			if !ok || value == types.NullValue {
+				break
+			}
+
+			tuple = append(tuple, value)
+		}
+
+		if len(tuple) != len(columns) {
+			continue
+		}
+
+		id := strings.Join(tuple, "\x1f")
+		if !seen[id] {
+			seen[id] = true
+			values = append(values, tuple)
+		}
+	}
+
+	return values
+}
+
+func quoteValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+//inClause return the condition selecting the rows with the columns values in the list.
+func inClause(columns []string, values [][]string) string {
+	quoted := make([]string, 0, len(values))
+
+	for _, tuple := range values {
+		q := make([]string, len(tuple))
+		for i, value := range tuple {
+			q[i] = quoteValue(value)
+		}
+
+		if len(columns) == 1 {
+			quoted = append(quoted, q[0])
+		} else {
+			quoted = append(quoted, fmt.Sprintf("(%s)", strings.Join(q, ",")))
+		}
+	}
+
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s IN (%s)", columns[0], strings.Join(quoted, ","))
+	}
+
+	return fmt.Sprintf("(%s) IN (%s)", strings.Join(columns, ","), strings.Join(quoted, ","))
+}
+
+//fetch reads the rows of the table with the columns values in the list.
+func (st *Step) fetch(ctx context.Context, log *logrus.Entry, table string, columns []string, values [][]string) ([]types.Record, error) {
+	records := make([]types.Record, 0)
+	t := st.subset.table(table)
+
+	for start := 0; start < len(values); start += subsetBatchSize {
+		end := start + subsetBatchSize
+		if end > len(values) {
+			end = len(values)
+		}
+
+		loader, err := st.prov.NewLoader(ctx, log, st.sourceCfg.ds, table, inClause(columns, values[start:end]), types.LoaderOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = t.setColumns(log, loader); err != nil {
+			loader.Close(log)
+			return nil, err
+		}
+
+		for loader.Next() {
+			record, err := loader.Load(log)
+			if err != nil {
+				loader.Close(log)
+				return nil, err
+			}
+
+			records = append(records, record)
+		}
+
+		loader.Close(log)
+	}
+
+	return records, nil
+}
+
+//follow adds the rows related to the pending rows by the foreign keys, children if asked, parents otherwise.
+func (st *Step) follow(ctx context.Context, log *logrus.Entry, queue []subsetPending, children bool) error {
+	for len(queue) != 0 && ctx.Err() == nil {
+		p := queue[0]
+		queue = queue[1:]
+
+		for _, fk := range st.subset.fks {
+			table, columns, related, relatedColumns := fk.RefTable, fk.RefColumns, fk.Table, fk.Columns
+			if children {
+				table, columns, related, relatedColumns = fk.Table, fk.Columns, fk.RefTable, fk.RefColumns
+			}
+
+			if related != p.table || (children && st.subset.exclude[table]) {
+				continue
+			}
+
+			values := tuples(p.records, relatedColumns)
+			if len(values) == 0 {
+				continue
+			}
+
+			records, err := st.fetch(ctx, log, table, columns, values)
+			if err != nil {
+				log.Errorf("Reading rows of %s related to %s failed", table, p.table)
+				log.Error(err)
+
+				return err
+			}
+
+			if added := st.subset.table(table).add(records); len(added) != 0 {
+				log.Debugf("%d rows of %s added by %s", len(added), table, fk.Name)
+				queue = append(queue, subsetPending{table: table, records: added})
+			}
+		}
+	}
+
+	return nil
+}
+
+//selectSubset reads the sample of the root table and follows the foreign keys to select the related rows.
+func (st *Step) selectSubset(ctx context.Context, log *logrus.Entry) error {
+	var err error
+
+	st.subset.fks, err = st.prov.ForeignKeys(ctx, log, st.sourceCfg.ds)
+	if err != nil {
+		log.Error("Retrieving foreign keys failed")
+		return err
+	}
+
+	st.subset.tables = make(map[string]*subsetTable)
+	root := st.subset.table(st.sourceCfg.table)
+
+	if err = root.setColumns(log, st.source); err != nil {
+		return err
+	}
+
+	records := make([]types.Record, 0)
+
+	for (st.subset.limit <= 0 || len(records) < st.subset.limit) && st.source.Next() {
+		record, err := st.source.Load(log)
+		if err != nil {
+			log.Error("Source reading failed:")
+			log.Error(err)
+
+			return err
+		}
+
+		records = append(records, record)
+	}
+
+	log.Infof("%d rows sampled from %s", len(records), root.name)
+
+	// The children of the sample, then the parents of all the rows selected, the children of the parents are not needed
+	err = st.follow(ctx, log, []subsetPending{{table: root.name, records: root.add(records)}}, true)
+	if err != nil {
+		return err
+	}
+
+	queue := make([]subsetPending, 0, len(st.subset.tables))
+	for _, name := range st.subsetOrder(log) {
+		queue = append(queue, subsetPending{table: name, records: st.subset.tables[name].records})
+	}
+
+	return st.follow(ctx, log, queue, false)
+}
+
+//subsetOrder return the selected tables, the parents before their children.
+func (st *Step) subsetOrder(log *logrus.Entry) []string {
+	names := make([]string, 0, len(st.subset.tables))
+	for name := range st.subset.tables {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	order := make([]string, 0, len(names))
+	done := make(map[string]bool)
+
+	for len(order) != len(names) {
+		progress := false
+
+		for _, name := range names {
+			if done[name] || !st.parentsDone(name, done) {
+				continue
+			}
+
+			done[name] = true
+			order = append(order, name)
+			progress = true
+		}
+
+		if progress {
+			continue
+		}
+
+		// Circular references, the remaining tables are written in alphabetical order
+		for _, name := range names {
+			if !done[name] {
+				log.Warnf("Circular foreign keys on %s, the insertion order may break them", name)
+
+				done[name] = true
+				order = append(order, name)
+			}
+		}
+	}
+
+	return order
+}
+
+//parentsDone return true if all the selected tables referenced by the table are already ordered.
+func (st *Step) parentsDone(name string, done map[string]bool) bool {
+	for _, fk := range st.subset.fks {
+		if fk.Table != name || fk.RefTable == name {
+			continue
+		}
+
+		if _, selected := st.subset.tables[fk.RefTable]; selected && !done[fk.RefTable] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//subsetSavers creates the savers of each selected table for all the destinations and truncates the tables if needed.
+func (st *Step) subsetSavers(ctx context.Context, log *logrus.Entry, order []string) (map[string][]provider.Saver, error) {
+	savers := make(map[string][]provider.Saver, len(order))
+
+	// The tables are truncated from the children to the parents
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		t := st.subset.tables[name]
+
+		for _, dest := range st.destsUsed {
+			// The key of the destination only applies to the root table
+			key := t.key
+			if key == "" && name == st.sourceCfg.table {
+				key = dest.key
+			}
+
+			// Each table is written by its own transaction which does not see the parents rows written by the others
+			options := types.SaverOptions{CreateTable: dest.createTable, Columns: t.columns, Merge: dest.merge, ResetSequence: dest.resetSeq, DisableForeignKeys: true, DisableTriggers: dest.disableTrig, TolerateErrors: st.maxErrors != 0}
+
+			saver, err := st.prov.NewSaver(ctx, log, dest.ds, name, key, dest.mode, options)
+			if err != nil {
+				return nil, err
+			}
+
+			// Added immediately to be closed or reset at the end of the step
+			st.destinations = append(st.destinations, saver)
+			savers[name] = append(savers[name], saver)
+
+			if truncater, ok := saver.(provider.Truncater); ok && dest.mode == "truncate" {
+				if err = truncater.Truncate(log); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return savers, nil
+}
+
+//writeSubset writes the selected rows of each table in all the destinations.
+func (st *Step) writeSubset(ctx context.Context, log *logrus.Entry) error {
+	limiters := st.limiters(st.sourceCfg.ds)
+	order := st.subsetOrder(log)
+
+	savers, err := st.subsetSavers(ctx, log, order)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range order {
+		t := st.subset.tables[name]
+
+		for _, saver := range savers[name] {
+			for _, record := range t.records {
+				records, err := st.applyFilters(ctx, log, record)
+				if err != nil {
+					return err
+				}
+
+				for _, record := range records {
+					for _, l := range limiters {
+						if err = l.Wait(ctx, 1, recordSize(record)); err != nil {
+							log.Debug("Synchronization cancelled")
+							return nil
+						}
+					}
+
+					if err = saver.Save(log, record); err != nil {
+						if err = st.reject(ctx, log, saver, record, err); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+
+		st.count += len(t.records)
+		log.Infof("   - %s: %d rows", name, len(t.records))
+	}
+
+	return nil
+}
+
+//doSubset synchronize a referentially consistent subset of the source tables.
+func (st *Step) doSubset(ctx context.Context, log *logrus.Entry) error {
+	err := st.selectSubset(ctx, log)
+	if err == nil && ctx.Err() == nil {
+		if st.dryRun {
+			log.Info("Dry run: the subset would contain")
+
+			for _, name := range st.subsetOrder(log) {
+				log.Infof("   - %s: %d rows", name, len(st.subset.tables[name].records))
+			}
+
+			return nil
+		}
+
+		log.Infof("Will synchronize a subset of %s to", st.source.Name())
+		err = st.writeSubset(ctx, log)
+	}
+
+	if err != nil && st.ignoreErrors {
+		log.Warnf("Ignoring error: %v", err)
+		return nil
+	}
+
+	if err != nil {
+		log.Error("Synchronization failed")
+		return err
+	}
+
+	log.Infof("Synchronization ok. %d rows", st.count)
+
+	return nil
+}
@@ -9,20 +9,33 @@ import (
 
 	"github.com/marema31/kamino/filter"
 	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
+	"github.com/marema31/kamino/throttle"
 )
 
 type parsedSourceConfig struct {
-	ds    datasource.Datasourcer
-	table string
-	where string
+	ds         datasource.Datasourcer
+	table      string
+	where      string
+	chunkSize  int
+	chunkPause time.Duration
+	union      []datasource.Datasourcer // Other datasources read after ds
+	origin     string                   // Column receiving the name of the datasource of the record
+	pairBy     string                   // Named tag used to pair the sources and the destinations
 }
 
 type parsedDestConfig struct {
-	ds      datasource.Datasourcer
-	table   string
-	key     string
-	mode    string
-	queries []common.SkipQuery
+	ds          datasource.Datasourcer
+	table       string
+	key         string
+	mode        string
+	queries     []common.SkipQuery
+	createTable bool
+	merge       types.MergePolicy
+	softDelete  types.SoftDelete
+	resetSeq    bool
+	disableFKs  bool
+	disableTrig bool
 }
 
 // Step informations.
@@ -31,17 +44,34 @@ type Step struct {
 	baseFolder     string
 	source         provider.Loader
 	cacheLoader    provider.Loader
+	fromCache      bool
 	cacheSaver     provider.Saver
 	cacheTTL       time.Duration
 	allowCacheOnly bool
 	forceCacheOnly bool
+	cacheKey       string
+	cacheAction    string
 	destinations   []provider.Saver
 	filters        []filter.Filter
+	lookups        []*lookupSource
 	sourceCfg      parsedSourceConfig
 	cacheCfg       parsedSourceConfig
 	destsCfg       []parsedDestConfig
+	destsUsed      []parsedDestConfig
 	prov           provider.Provider
 	dryRun         bool
+	dryRunSample   int
+	plans          []*destPlan
+	verify         *verifyConfig
+	checkpoint     *checkpointConfig
+	limiter        *throttle.Limiter
+	subset         *subsetConfig
+	rejectsCfg     parsedSourceConfig
+	rejectsSaver   provider.Saver
+	maxErrors      int
+	progress       *progressConfig
+	errors         int
+	rejected       int
 	count          int
 	ignoreErrors   bool
 }
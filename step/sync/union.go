@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/datasource"
+	"github.com/marema31/kamino/provider"
+	"github.com/marema31/kamino/provider/types"
+)
+
+//unionLoader read in sequence the sources selected by the step and inject their name in the records.
+type unionLoader struct {
+	loaders []provider.Loader
+	names   []string
+	origin  string
+	current int
+}
+
+//datasources return all the datasources read by the source, in reading order.
+func (p parsedSourceConfig) datasources() []datasource.Datasourcer {
+	return append([]datasource.Datasourcer{p.ds}, p.union...)
+}
+
+//name return the name of the source datasources.
+func (p parsedSourceConfig) name() string {
+	names := make([]string, 0, len(p.union)+1)
+	for _, ds := range p.datasources() {
+		names = append(names, ds.GetName())
+	}
+
+	return strings.Join(names, "+")
+}
+
+//newSourceLoader create the loader of the source, the union of all its datasources if needed.
+func (st *Step) newSourceLoader(ctx context.Context, log *logrus.Entry, where string, options types.LoaderOptions) (provider.Loader, error) {
+	if len(st.sourceCfg.union) == 0 && st.sourceCfg.origin == "" {
+		return st.prov.NewLoader(ctx, log, st.sourceCfg.ds, st.sourceCfg.table, where, options)
+	}
+
+	ul := &unionLoader{origin: st.sourceCfg.origin}
+
+	for _, ds := range st.sourceCfg.datasources() {
+		loader, err := st.prov.NewLoader(ctx, log, ds, st.sourceCfg.table, where, options)
+		if err != nil {
+			ul.Close(log)
+			return nil, err
+		}
+
+		ul.loaders = append(ul.loaders, loader)
+		ul.names = append(ul.names, ds.GetName())
+	}
+
+	return ul, nil
+}
+
+//Next moves to next record and return false if there is no more records, switching to the next source if needed.
+func (ul *unionLoader) Next() bool {
+	for ul.current < len(ul.loaders) {
+		if ul.loaders[ul.current].Next() {
+			return true
+		}
+
+		ul.current++
+	}
+
+	return false
+}
+
+//Load reads the next record of the current source and return it.
+func (ul *unionLoader) Load(log *logrus.Entry) (types.Record, error) {
+	record, err := ul.loaders[ul.current].Load(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if ul.origin != "" {
+		record[ul.origin] = ul.names[ul.current]
+	}
+
+	return record, nil
+}
+
+//Close closes all the sources.
+func (ul *unionLoader) Close(log *logrus.Entry) error {
+	var firstErr error
+
+	for _, loader := range ul.loaders {
+		if err := loader.Close(log); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+//Name give the name of the sources.
+func (ul *unionLoader) Name() string {
+	names := make([]string, 0, len(ul.loaders))
+	for _, loader := range ul.loaders {
+		names = append(names, loader.Name())
+	}
+
+	return strings.Join(names, "+")
+}
+
+//Columns return the columns of the first source with the origin column.
+func (ul *unionLoader) Columns(log *logrus.Entry) ([]types.Column, error) {
+	columns, err := ul.loaders[0].Columns(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if ul.origin != "" {
+		columns = append(columns, types.Column{Name: ul.origin})
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,169 @@
+package sync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/marema31/kamino/provider/types"
+)
+
+var errVerify = errors.New("VERIFICATION FAILED")
+
+// checksum order independent summary of a dataset.
+type checksum struct {
+	count int
+	sum   uint64
+}
+
+// verifyConfig contains the columns used for the post synchronization verification and the summary of the records saved in each destination.
+type verifyConfig struct {
+	columns []string
+	saved   []checksum
+}
+
+//add accounts the record in the summary, the sum of the hash of each record does not depend of the records order.
+func (c *checksum) add(record types.Record, columns []string) {
+	c.count++
+
+	if len(columns) == 0 {
+		return
+	}
+
+	h := sha256.New()
+
+	for _, col := range columns {
+		// The separators avoid collision between ("ab","c") and ("a","bc")
+		fmt.Fprintf(h, "%s\x1f%s\x1e", col, record[col])
+	}
+
+	c.sum += binary.BigEndian.Uint64(h.Sum(nil))
+}
+
+//aliveWhere return the condition selecting the rows of the destination not marked as deleted.
+func aliveWhere(softDelete types.SoftDelete) string {
+	if softDelete.Column == "" {
+		return ""
+	}
+
+	if strings.EqualFold(softDelete.Alive, "NULL") {
+		return fmt.Sprintf("%s IS NULL", softDelete.Column)
+	}
+
+	return fmt.Sprintf("%s = %s", softDelete.Column, softDelete.Alive)
+}
+
+//destChecksum read the destination to compute its summary.
+func (st *Step) destChecksum(ctx context.Context, log *logrus.Entry, dest parsedDestConfig) (checksum, error) {
+	var c checksum
+
+	// The soft deleted rows are kept in the destination but are not part of the copy
+	loader, err := st.prov.NewLoader(ctx, log, dest.ds, dest.table, aliveWhere(dest.softDelete), types.LoaderOptions{})
+	if err != nil {
+		return c, err
+	}
+
+	defer loader.Close(log)
+
+	for loader.Next() {
+		record, err := loader.Load(log)
+		if err != nil {
+			return c, err
+		}
+
+		c.add(record, st.verify.columns)
+	}
+
+	return c, nil
+}
+
+//verifyDestinations compare the row count and checksum of the records saved in each destination with the ones read from it.
+func (st *Step) verifyDestinations(ctx context.Context, log *logrus.Entry) error {
+	failed := false
+
+	for i, dest := range st.destsUsed {
+		mode := planMode(dest)
+		if mode != "exactcopy" && mode != "truncate" {
+			log.Warnf("Verification skipped for %s(%s), the mode %s does not produce a copy of the source", dest.ds.GetName(), dest.table, mode)
+			continue
+		}
+
+		c, err := st.destChecksum(ctx, log, dest)
+		if err != nil {
+			log.Error("Reading destination for verification failed:")
+			log.Error(err)
+
+			return err
+		}
+
+		saved := st.verify.saved[i]
+
+		switch {
+		case c.count != saved.count:
+			log.Errorf("Verification of %s(%s) failed: %d rows synchronized, %d rows in destination", dest.ds.GetName(), dest.table, saved.count, c.count)
+
+			failed = true
+		case c.sum != saved.sum:
+			log.Errorf("Verification of %s(%s) failed: the checksum of columns %v differs", dest.ds.GetName(), dest.table, st.verify.columns)
+
+			failed = true
+		default:
+			log.Infof("Verification of %s(%s) ok: %d rows", dest.ds.GetName(), dest.table, c.count)
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("destinations are not identical to the source: %w", errVerify)
+	}
+
+	return nil
+}
+
+//commitDestinations close the destinations before the end of the step to be able to verify them,
+//they are committed before the other steps of the same priority are finished.
+func (st *Step) commitDestinations(log *logrus.Entry) error {
+	for len(st.destinations) != 0 {
+		d := st.destinations[0]
+		// Remove it from the list before closing it to ensure Finish will not close it a second time
+		st.destinations = st.destinations[1:]
+
+		if err := d.Close(log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//verifyAfterSync commit the destinations and verify them if the step asked for it.
+func (st *Step) verifyAfterSync(ctx context.Context, log *logrus.Entry) error {
+	if st.verify == nil {
+		return nil
+	}
+
+	// The step has been cancelled, the destinations will be reset by Cancel
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if st.checkpoint != nil && st.checkpoint.resumed {
+		log.Warn("Verification skipped, the synchronization has been resumed from a checkpoint")
+		return nil
+	}
+
+	err := st.commitDestinations(log)
+	if err == nil {
+		err = st.verifyDestinations(ctx, log)
+	}
+
+	if err != nil && st.ignoreErrors {
+		log.Warnf("Ignoring error: %v", err)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,111 @@
+//Package throttle limits the throughput of the synchronizations
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter paces the records to not exceed a number of rows and/or bytes by second.
+type Limiter struct {
+	mu        sync.Mutex
+	rows      int // maximum number of rows by second, 0 for no limit
+	bytes     int // maximum number of bytes by second, 0 for no limit
+	nextRows  time.Time
+	nextBytes time.Time
+}
+
+var (
+	hostsMu sync.Mutex
+	hosts   = map[string]*Limiter{}
+)
+
+//New returns a limiter for the rows and bytes by second provided, nil if there is no limit.
+func New(rows int, bytes int) *Limiter {
+	if rows <= 0 && bytes <= 0 {
+		return nil
+	}
+
+	return &Limiter{rows: rows, bytes: bytes}
+}
+
+//strictest return the lowest limit, 0 being no limit.
+func strictest(current int, requested int) int {
+	if current <= 0 || (requested > 0 && requested < current) {
+		return requested
+	}
+
+	return current
+}
+
+//ForHost returns the limiter shared by all the users of the host, the limits are the strictest of all the calls.
+func ForHost(host string, rows int, bytes int) *Limiter {
+	if rows <= 0 && bytes <= 0 {
+		return nil
+	}
+
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+
+	if l, ok := hosts[host]; ok {
+		l.mu.Lock()
+		l.rows = strictest(l.rows, rows)
+		l.bytes = strictest(l.bytes, bytes)
+		l.mu.Unlock()
+
+		return l
+	}
+
+	l := New(rows, bytes)
+	hosts[host] = l
+
+	return l
+}
+
+//reserve accounts the amount and return the time when it is allowed.
+func reserve(next *time.Time, now time.Time, amount int, rate int) time.Time {
+	if rate <= 0 {
+		return now
+	}
+
+	at := *next
+	if at.Before(now) {
+		at = now
+	}
+
+	*next = at.Add(time.Duration(amount) * time.Second / time.Duration(rate))
+
+	return at
+}
+
+//Wait blocks until the rows and bytes can be transferred without exceeding the limits or the context is cancelled.
+func (l *Limiter) Wait(ctx context.Context, rows int, bytes int) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := reserve(&l.nextRows, now, rows, l.rows)
+
+	if atBytes := reserve(&l.nextBytes, now, bytes, l.bytes); atBytes.After(at) {
+		at = atBytes
+	}
+	l.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
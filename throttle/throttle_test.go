@@ -0,0 +1,86 @@
+package throttle_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/marema31/kamino/throttle"
+)
+
+func TestNoLimit(t *testing.T) {
+	l := throttle.New(0, 0)
+	if l != nil {
+		t.Fatalf("New should return nil without limits")
+	}
+
+	if err := l.Wait(context.Background(), 1000, 1000); err != nil {
+		t.Errorf("Wait should not return error, returned %v", err)
+	}
+}
+
+func TestRowsLimit(t *testing.T) {
+	l := throttle.New(100, 0)
+	start := time.Now()
+
+	for i := 0; i < 11; i++ {
+		if err := l.Wait(context.Background(), 1, 10); err != nil {
+			t.Fatalf("Wait should not return error, returned %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("11 rows at 100 rows/s should last at least 100ms, lasted %v", elapsed)
+	}
+}
+
+func TestBytesLimitCancel(t *testing.T) {
+	l := throttle.New(0, 10)
+
+	if err := l.Wait(context.Background(), 1, 100); err != nil {
+		t.Fatalf("First Wait should not return error, returned %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx, 1, 100); err == nil {
+		t.Errorf("Wait should return error when the context is cancelled")
+	}
+}
+
+func TestForHost(t *testing.T) {
+	l1 := throttle.ForHost("db1:3306", 10, 0)
+	l2 := throttle.ForHost("db1:3306", 20, 0)
+	l3 := throttle.ForHost("db2:3306", 10, 0)
+
+	if l1 != l2 {
+		t.Errorf("ForHost should return the same limiter for the same host")
+	}
+
+	if l1 == l3 {
+		t.Errorf("ForHost should return different limiters for different hosts")
+	}
+
+	if throttle.ForHost("db3:3306", 0, 0) != nil {
+		t.Errorf("ForHost should return nil without limits")
+	}
+}
+
+func TestForHostStrictest(t *testing.T) {
+	throttle.ForHost("db4:3306", 0, 1000000)
+	l := throttle.ForHost("db4:3306", 100, 0)
+	throttle.ForHost("db4:3306", 1000, 0)
+
+	start := time.Now()
+
+	for i := 0; i < 11; i++ {
+		if err := l.Wait(context.Background(), 1, 10); err != nil {
+			t.Fatalf("Wait should not return error, returned %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("The strictest limit of 100 rows/s should be used, 11 rows lasted %v", elapsed)
+	}
+}